@@ -0,0 +1,255 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// AsyncValidator checks the value of an input asynchronously, e.g. by asking a
+// server whether a username is still available. It returns nil if the value
+// is valid, or an error whose message is used as the message of the resulting
+// ValidationError. An AsyncValidator runs in its own goroutine, so it may
+// block. It should return as soon as possible when ctx is cancelled, which
+// happens when its result is no longer needed.
+type AsyncValidator func(ctx context.Context, value string) error
+
+// AsyncValidation runs AsyncValidators for the inputs of a form and keeps
+// track of their results across validations. Because the checks take a while,
+// an AsyncValidation usually outlives the Form it validates: call Validate
+// with a newly parsed Form every time the form is validated, e.g. from the
+// validation function of a LiveValidation. Each call starts checks for the
+// inputs whose values changed, cancels the checks for their previous values,
+// and adds the errors from the checks which have already completed to the
+// form. Checks for values which did not change are not run again.
+//
+// Like most validations, AsyncValidation skips inputs which are empty or not
+// included in the form. The Code of the errors it adds is empty, just like for
+// InputValidation.AddError. The methods of AsyncValidation are safe for
+// concurrent use.
+type AsyncValidation struct {
+	// OnResult, if non-nil, is called with the name of the input when a check
+	// completes without being cancelled. Use it to validate the form again, so
+	// that the result is added to the form. It is called on the goroutine of
+	// the check.
+	OnResult   func(inputName string)
+	mut        sync.Mutex
+	validators map[string][]AsyncValidator
+	checks     map[string]*asyncCheck
+}
+
+// asyncCheck is a run of the AsyncValidators for a single value of an input.
+type asyncCheck struct {
+	value  string
+	cancel context.CancelFunc
+	// done is closed when the check has completed or was cancelled.
+	done chan struct{}
+	// err is the first error returned by the validators. It must not be read
+	// before done is closed.
+	err error
+}
+
+// NewAsyncValidation creates and returns an AsyncValidation without any
+// validators.
+func NewAsyncValidation() *AsyncValidation {
+	return &AsyncValidation{
+		validators: map[string][]AsyncValidator{},
+		checks:     map[string]*asyncCheck{},
+	}
+}
+
+// Add adds validator to the validators for the input identified by inputName.
+// The validators for an input are run one after the other, in the order they
+// were added, and the check stops at the first error. Adding a validator
+// cancels the current check for the input, if any.
+func (av *AsyncValidation) Add(inputName string, validator AsyncValidator) {
+	av.mut.Lock()
+	defer av.mut.Unlock()
+	av.validators[inputName] = append(av.validators[inputName], validator)
+	av.cancelCheck(inputName)
+}
+
+// Validate starts checks for the inputs in form whose values differ from the
+// values of their current checks, cancelling the superseded checks, and adds
+// a ValidationError to form for each completed check which failed. Checks
+// which are still pending do not add errors, so use Valid instead of
+// form.HasErrors to decide whether the form is valid.
+func (av *AsyncValidation) Validate(form *Form) {
+	av.mut.Lock()
+	names := make([]string, 0, len(av.validators))
+	for name := range av.validators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	failed := map[string]error{}
+	for _, name := range names {
+		value := inputValue(form, name)
+		check := av.checks[name]
+		if check == nil || check.value != value {
+			av.cancelCheck(name)
+			if value == "" {
+				continue
+			}
+			check = av.startCheck(name, value)
+		}
+		select {
+		case <-check.done:
+			if check.err != nil {
+				failed[name] = check.err
+			}
+		default:
+		}
+	}
+	av.mut.Unlock()
+	for _, name := range names {
+		if err, found := failed[name]; found {
+			form.Validate(name).AddError("%s", err.Error())
+		}
+	}
+}
+
+// inputValue returns the value of the input identified by inputName, or an
+// empty string if the form does not include the input.
+func inputValue(form *Form, inputName string) string {
+	if input := form.Inputs[inputName]; input != nil {
+		return input.value()
+	}
+	return ""
+}
+
+// startCheck starts a check of value for the input identified by inputName. The
+// caller must hold av.mut.
+func (av *AsyncValidation) startCheck(inputName string, value string) *asyncCheck {
+	ctx, cancel := context.WithCancel(context.Background())
+	check := &asyncCheck{
+		value:  value,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	av.checks[inputName] = check
+	validators := av.validators[inputName]
+	go func() {
+		var err error
+		for _, validator := range validators {
+			// Don't start any more validators once the check is cancelled.
+			if ctx.Err() != nil {
+				break
+			}
+			if err = validator(ctx, value); err != nil {
+				break
+			}
+		}
+		av.mut.Lock()
+		cancelled := ctx.Err() != nil
+		if !cancelled {
+			check.err = err
+		}
+		close(check.done)
+		av.mut.Unlock()
+		cancel()
+		if !cancelled && av.OnResult != nil {
+			av.OnResult(inputName)
+		}
+	}()
+	return check
+}
+
+// cancelCheck cancels and forgets the current check for the input identified
+// by inputName, if any. The caller must hold av.mut.
+func (av *AsyncValidation) cancelCheck(inputName string) {
+	if check := av.checks[inputName]; check != nil {
+		check.cancel()
+		delete(av.checks, inputName)
+	}
+}
+
+// Pending returns true iff a check for the input identified by inputName has
+// been started and has not completed yet.
+func (av *AsyncValidation) Pending(inputName string) bool {
+	av.mut.Lock()
+	defer av.mut.Unlock()
+	check := av.checks[inputName]
+	return check != nil && isPending(check)
+}
+
+// HasPending returns true iff at least one check has been started and has not
+// completed yet.
+func (av *AsyncValidation) HasPending() bool {
+	av.mut.Lock()
+	defer av.mut.Unlock()
+	for _, check := range av.checks {
+		if isPending(check) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPending returns true iff check has not completed yet.
+func isPending(check *asyncCheck) bool {
+	select {
+	case <-check.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// Valid returns true iff form has no errors and every input in form which has
+// validators has been checked with its current value and passed. In
+// particular, Valid returns false while any check for the form is still
+// pending, and if form has changed since it was last passed to Validate.
+func (av *AsyncValidation) Valid(form *Form) bool {
+	if form.HasErrors() {
+		return false
+	}
+	av.mut.Lock()
+	defer av.mut.Unlock()
+	for name := range av.validators {
+		value := inputValue(form, name)
+		if value == "" {
+			continue
+		}
+		check := av.checks[name]
+		if check == nil || check.value != value || isPending(check) || check.err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Wait blocks until all the pending checks have completed or ctx is done. It
+// returns ctx.Err() if ctx is done first, and nil otherwise. Call Validate
+// again after Wait to add the results to the form.
+func (av *AsyncValidation) Wait(ctx context.Context) error {
+	av.mut.Lock()
+	dones := make([]chan struct{}, 0, len(av.checks))
+	for _, check := range av.checks {
+		dones = append(dones, check.done)
+	}
+	av.mut.Unlock()
+	for _, done := range dones {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Cancel cancels all the pending checks and forgets the results of all the
+// completed checks, so that every input is checked again by the next call to
+// Validate.
+func (av *AsyncValidation) Cancel() {
+	av.mut.Lock()
+	defer av.mut.Unlock()
+	for name := range av.checks {
+		av.cancelCheck(name)
+	}
+}
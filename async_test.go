@@ -0,0 +1,171 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubValidator is an AsyncValidator which blocks until it is released, so
+// that tests can control when checks complete.
+type stubValidator struct {
+	mut      sync.Mutex
+	calls    []string
+	release  chan struct{}
+	taken    map[string]bool
+	started  chan string
+	canceled chan string
+}
+
+func newStubValidator(taken ...string) *stubValidator {
+	stub := &stubValidator{
+		release:  make(chan struct{}),
+		taken:    map[string]bool{},
+		started:  make(chan string, 10),
+		canceled: make(chan string, 10),
+	}
+	for _, value := range taken {
+		stub.taken[value] = true
+	}
+	return stub
+}
+
+func (stub *stubValidator) validate(ctx context.Context, value string) error {
+	stub.mut.Lock()
+	stub.calls = append(stub.calls, value)
+	stub.mut.Unlock()
+	stub.started <- value
+	select {
+	case <-stub.release:
+	case <-ctx.Done():
+		stub.canceled <- value
+		return ctx.Err()
+	}
+	if stub.taken[value] {
+		return errors.New(value + " is already taken.")
+	}
+	return nil
+}
+
+func (stub *stubValidator) callCount() int {
+	stub.mut.Lock()
+	defer stub.mut.Unlock()
+	return len(stub.calls)
+}
+
+func waitForChecks(t *testing.T, av *AsyncValidation) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := av.Wait(ctx); err != nil {
+		t.Fatalf("Unexpected error from Wait: %s", err)
+	}
+}
+
+func TestAsyncValidation(t *testing.T) {
+	stub := newStubValidator("taken")
+	av := NewAsyncValidation()
+	av.Add("username", stub.validate)
+	results := make(chan string, 10)
+	av.OnResult = func(inputName string) {
+		results <- inputName
+	}
+
+	// The first check is pending, so the form is not valid yet.
+	form := NewForm(newTestInput("username", InputText, "taken"))
+	av.Validate(form)
+	if !av.Pending("username") || !av.HasPending() {
+		t.Error("Expected a pending check for username")
+	}
+	if form.HasErrors() || av.Valid(form) {
+		t.Errorf("Expected no errors and an invalid form while the check is pending, but got %v", form.Errors)
+	}
+	<-stub.started
+
+	// Changing the value supersedes the pending check.
+	form = NewForm(newTestInput("username", InputText, "free"))
+	av.Validate(form)
+	close(stub.release)
+	waitForChecks(t, av)
+	if got := <-results; got != "username" {
+		t.Errorf("Expected OnResult to be called for username but got %s", got)
+	}
+	if av.Pending("username") {
+		t.Error("Expected no pending check after Wait")
+	}
+	av.Validate(form)
+	if form.HasErrors() || !av.Valid(form) {
+		t.Errorf("Expected the form to be valid but got %v", form.Errors)
+	}
+	if got := <-stub.canceled; got != "taken" {
+		t.Errorf("Expected the check for taken to be canceled but got %s", got)
+	}
+	select {
+	case got := <-results:
+		t.Errorf("Expected no result for the canceled check but got %s", got)
+	default:
+	}
+
+	// Validating the same value again does not run the validator again.
+	calls := stub.callCount()
+	form = NewForm(newTestInput("username", InputText, "free"))
+	av.Validate(form)
+	if stub.callCount() != calls || !av.Valid(form) {
+		t.Error("Expected the result for an unchanged value to be reused")
+	}
+
+	// A failed check adds a ValidationError.
+	form = NewForm(newTestInput("username", InputText, "taken"))
+	av.Validate(form)
+	waitForChecks(t, av)
+	av.Validate(form)
+	if !form.HasErrorsFor("username") || form.FirstError("username").Error() != "taken is already taken." {
+		t.Errorf("Expected an error for username but got %v", form.Errors)
+	}
+	if av.Valid(form) {
+		t.Error("Expected the form not to be valid after a failed check")
+	}
+
+	// Empty inputs are skipped.
+	form = NewForm(newTestInput("username", InputText, ""))
+	av.Validate(form)
+	if av.HasPending() || form.HasErrors() || !av.Valid(form) {
+		t.Errorf("Expected an empty input to be skipped but got %v", form.Errors)
+	}
+
+	// A form which has not been validated with its current values is not valid.
+	form = NewForm(newTestInput("username", InputText, "other"))
+	if av.Valid(form) {
+		t.Error("Expected a form with unchecked values not to be valid")
+	}
+}
+
+func TestAsyncValidationCancel(t *testing.T) {
+	stub := newStubValidator()
+	av := NewAsyncValidation()
+	av.Add("coupon", stub.validate)
+	form := NewForm(newTestInput("coupon", InputText, "SAVE10"))
+	av.Validate(form)
+	av.Cancel()
+	if av.HasPending() {
+		t.Error("Expected no pending checks after Cancel")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	av.Validate(form)
+	if err := av.Wait(ctx); err != context.Canceled {
+		t.Errorf("Expected Wait to return context.Canceled but got %v", err)
+	}
+	close(stub.release)
+	waitForChecks(t, av)
+	av.Validate(form)
+	if !av.Valid(form) {
+		t.Errorf("Expected the form to be valid after checking it again but got %v", form.Errors)
+	}
+}
@@ -3,6 +3,8 @@ package form
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -27,20 +29,54 @@ type InputBinder interface {
 // inputBinderType is the reflect.Type of the InputBinder interface.
 var inputBinderType = reflect.TypeOf([]InputBinder{}).Elem()
 
+// timeType is the reflect.Type of time.Time.
+var timeType = reflect.TypeOf(time.Time{})
+
+// clockType is the reflect.Type of Clock.
+var clockType = reflect.TypeOf(Clock{})
+
+// weekType is the reflect.Type of Week.
+var weekType = reflect.TypeOf(Week{})
+
 // Bind attempts to bind the form input values to v, which must be a pointer to
 // a struct. Bind performs a one-way, one-time binding. Changes to the form
 // input values will not automatically update v, nor will changes to v
-// automatically change the form input values.
+// automatically change the form input values. In the browser, use BindLive to
+// create a live, two-way binding instead.
 //
 // The following struct field types are supported: string, []byte, int, int8,
 // int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64,
-// bool, time.Time, and pointers to any of the preceding types. Bind attempts to
-// match struct field names with input names in a case-insensitive manner. So an
-// input with a name attribute "foo" will be assigned to the field v.Foo. Bind
-// will simply ignore fields of v which do not match any input names and input
-// names which do not match any fields of v. Because Bind uses reflection, only
-// exported fields of v (those which start with a capital letter) will be
-// affected.
+// bool, time.Time, Clock, Week, and pointers to any of the preceding types.
+// Bind attempts to match struct field names with input names in a
+// case-insensitive manner. So an input with a name attribute "foo" will be
+// assigned to the field v.Foo. Bind will simply ignore fields of v which do not
+// match any input names and input names which do not match any fields of v.
+// Because Bind uses reflection, only exported fields of v (those which start
+// with a capital letter) will be affected.
+//
+// The matching can be customized with the "form" key in the struct field's
+// tag. The tag consists of an optional input name followed by a
+// comma-separated list of options. If the name is not empty, the field only
+// matches an input with exactly that name. A tag of "-" causes the field to be
+// skipped. The "omitempty" option causes the field to be left untouched if the
+// input value is empty, and the "required" option causes Bind to return an
+// error if there is no matching input or if the input value is empty. An input
+// which the browser would not submit, such as a group of radio buttons none of
+// which is checked, counts as empty and leaves the field untouched. Bool fields
+// and fields which implement InputBinder are the exception, so that an
+// unchecked checkbox sets a bool field to false. Examples:
+//
+//	// Field is bound to the input named "first-name".
+//	FirstName string `form:"first-name"`
+//
+//	// Field is ignored by Bind.
+//	Secret string `form:"-"`
+//
+//	// Field is bound to the input named "age", unless its value is empty.
+//	Age int `form:"age,omitempty"`
+//
+//	// Field is bound to the input named "email", which must not be empty.
+//	Email string `form:"email,required"`
 //
 // If v implements Binder, form.Bind will just call v.BindForm. Similarly if any
 // of the fields of v implement InputBinder, Bind will call BindInput on the
@@ -48,9 +84,36 @@ var inputBinderType = reflect.TypeOf([]InputBinder{}).Elem()
 // using a series of conversion rules. If a field type is not supported and that
 // field does not implement InputBinder, Bind will return an error.
 //
-// Bind will return an error if the type of v is not a pointer to a struct or if
-// there is an error arising from binding any of the individual inputs to
-// fields.
+// Fields which are themselves structs (or pointers to structs) are bound
+// recursively, except for time.Time, Clock, Week, and types which implement
+// InputBinder. An input matches a nested field if its name consists of the
+// outer and inner names joined by a dot or wrapped in brackets. So an input
+// named "billing.street" or "billing[street]" will be assigned to the field
+// v.Billing.Street. Nil pointers to structs are only allocated if there is at
+// least one input whose name begins with the name of the field. The fields of
+// anonymous embedded structs are promoted, i.e. they are matched as if they
+// were fields of v itself, just like in the encoding/json package. That
+// includes the rules for conflicting names: a field of v hides a promoted field
+// with the same name, and so does a field which is promoted from a shallower
+// depth. An embedded struct with a name in its form tag is treated like any
+// other nested struct.
+//
+// Slices and arrays of any of the supported types (including structs) are
+// bound from inputs which share the same name, such as a group of checkboxes
+// named "tags" or "tags[]", or from indexed inputs such as "items[0]" and
+// "items[1]". Only the values that the browser would submit are used, so
+// unchecked checkboxes are skipped. Indexed elements are ordered by index, but
+// gaps are removed, so "items[0]" and "items[2]" result in a slice of length
+// two. Maps with string keys are bound from inputs such as "attrs[color]".
+// Slices and maps are replaced entirely, but only if at least one matching
+// input exists. So a slice bound from a group of checkboxes is set to an empty
+// slice if none of them are checked.
+//
+// Bind will return an error if the type of v is not a pointer to a struct. If
+// any of the individual inputs cannot be bound to their fields, Bind continues
+// binding the remaining fields and then returns a BindErrors which holds a
+// BindError for each failure. Use Form.AddBindErrors to merge them into the
+// form errors alongside validation errors.
 func (form *Form) Bind(v interface{}) error {
 	// If v implements Binder, call v.BindForm.
 	if binder, ok := v.(Binder); ok {
@@ -69,22 +132,514 @@ func (form *Form) Bind(v interface{}) error {
 	if ptrVal.IsNil() {
 		return fmt.Errorf("form: Argument to Bind was nil")
 	}
-	val := ptrVal.Elem()
-	// Iterate through the fields of v and the inputs of form and find
-	// case-insenstive name matches.
+	ctx := &bindContext{form: form}
+	ctx.bindStruct(ptrVal.Elem(), nil, "")
+	if len(ctx.errs) > 0 {
+		return ctx.errs
+	}
+	return nil
+}
+
+// bindContext holds the state for a single call to Bind.
+type bindContext struct {
+	form *Form
+	// errs holds the errors for every input which could not be bound so far.
+	errs BindErrors
+}
+
+// addError adds a BindError to ctx.errs. input may be nil if the error is not
+// associated with a specific input (e.g. a required input is missing), in which
+// case inputName is used as the name of the input.
+func (ctx *bindContext) addError(fieldName string, fieldType reflect.Type, inputName string, input *Input, err error) {
+	bindErr := &BindError{
+		Field:     fieldName,
+		InputName: inputName,
+		Err:       err,
+		fieldType: fieldType,
+	}
+	if input != nil {
+		bindErr.InputName = input.Name
+		bindErr.InputType = input.Type
+		bindErr.RawValue = input.RawValue
+	}
+	ctx.errs = append(ctx.errs, bindErr)
+}
+
+// bindStruct binds the inputs of form to the fields of structVal, which must be
+// a settable struct value. path holds the name segments which correspond to
+// structVal itself. It is empty for the struct passed to Bind, and grows by one
+// segment for each level of nested struct fields. fieldPrefix is the qualified
+// name of structVal (e.g. "Billing") which is used in errors. Any errors are
+// added to ctx.errs.
+func (ctx *bindContext) bindStruct(structVal reflect.Value, path []pathSegment, fieldPrefix string) {
+	form := ctx.form
+	for _, sf := range structFields(structVal.Type()) {
+		field, tag := sf.field, sf.tag
+		fieldVal, ok := form.bindableField(structVal, sf.index, path)
+		if !ok {
+			continue
+		}
+		fieldName := field.Name
+		if fieldPrefix != "" {
+			fieldName = fieldPrefix + "." + field.Name
+		}
+		fieldPath := appendPath(path, fieldSegments(field, tag)...)
+		if isNestedStruct(field.Type) {
+			// Only allocate nil pointers to structs if there is at least one
+			// input which could be bound to one of their fields.
+			if field.Type.Kind() == reflect.Ptr && fieldVal.IsNil() && !form.hasInputsWithPrefix(fieldPath) {
+				continue
+			}
+			ctx.bindStruct(indirect(fieldVal), fieldPath, fieldName)
+			continue
+		}
+		if isCollection(field.Type) {
+			found := ctx.bindCollection(fieldVal, fieldPath, fieldName)
+			if tag.required {
+				if !found {
+					ctx.addError(fieldName, field.Type, joinPath(fieldPath), nil, errRequiredMissing)
+				} else if isEmptyCollection(fieldVal) {
+					ctx.addError(fieldName, field.Type, joinPath(fieldPath), nil, errRequiredEmpty)
+				}
+			}
+			continue
+		}
+		input := form.inputForPath(fieldPath)
+		if input == nil {
+			if tag.required {
+				ctx.addError(fieldName, field.Type, joinPath(fieldPath), nil, errRequiredMissing)
+			}
+			continue
+		}
+		if input.isOmitted() && !bindsUnsubmitted(field.Type) {
+			// The browser would not submit the input, e.g. because none of the
+			// radio buttons in a group is checked, so leave the field alone.
+			if tag.required {
+				ctx.addError(fieldName, field.Type, "", input, errRequiredEmpty)
+			}
+			continue
+		}
+		if input.RawValue == "" {
+			if tag.required {
+				ctx.addError(fieldName, field.Type, "", input, errRequiredEmpty)
+				continue
+			}
+			if tag.omitEmpty {
+				continue
+			}
+		}
+		// Attempt to bind the input to the field.
+		if err := bindInput(field.Type, fieldVal, input); err != nil {
+			ctx.addError(fieldName, field.Type, "", input, err)
+		}
+	}
+}
+
+// bindableField returns the field of structVal identified by index, which may
+// be the index of a field promoted from an embedded struct. Nil pointers to
+// embedded structs along the way are allocated, but only if there is at least
+// one input under path, which holds the name segments which correspond to
+// structVal. Otherwise bindableField returns false.
+func (form *Form) bindableField(structVal reflect.Value, index []int, path []pathSegment) (reflect.Value, bool) {
+	fieldVal := structVal.Field(index[0])
+	for _, i := range index[1:] {
+		if fieldVal.Kind() == reflect.Ptr {
+			if fieldVal.IsNil() {
+				if !form.hasInputsWithPrefix(path) {
+					// There is no reason to allocate if there are no inputs.
+					return reflect.Value{}, false
+				}
+				fieldVal.Set(reflect.New(fieldVal.Type().Elem()))
+			}
+			fieldVal = fieldVal.Elem()
+		}
+		fieldVal = fieldVal.Field(i)
+	}
+	return fieldVal, true
+}
+
+// bindsUnsubmitted returns true iff a field with the given type should be bound
+// to an input even if the browser would not submit it. This is the case for
+// bool fields, which are set to false by an unchecked checkbox, and for fields
+// which implement InputBinder, which can inspect the input themselves.
+func bindsUnsubmitted(fieldType reflect.Type) bool {
+	if fieldType.Implements(inputBinderType) || reflect.PtrTo(fieldType).Implements(inputBinderType) {
+		return true
+	}
+	return getUnderlyingFieldType(fieldType).Kind() == reflect.Bool
+}
+
+// isNestedStruct returns true iff fieldType is a struct or a pointer to a
+// struct which Bind should descend into, i.e. one which is not time.Time, Clock,
+// or Week and does not implement InputBinder.
+func isNestedStruct(fieldType reflect.Type) bool {
+	if fieldType.Implements(inputBinderType) || reflect.PtrTo(fieldType).Implements(inputBinderType) {
+		return false
+	}
+	underlyingType := getUnderlyingFieldType(fieldType)
+	switch underlyingType {
+	case timeType, clockType, weekType:
+		return false
+	}
+	return underlyingType.Kind() == reflect.Struct
+}
+
+// isCollection returns true iff fieldType is a slice, array, or map (or a
+// pointer to one) which Bind should fill from multiple inputs. []byte is not
+// considered a collection since it is bound from a single input, and neither
+// are types which implement InputBinder.
+func isCollection(fieldType reflect.Type) bool {
+	if fieldType.Implements(inputBinderType) || reflect.PtrTo(fieldType).Implements(inputBinderType) {
+		return false
+	}
+	underlyingType := getUnderlyingFieldType(fieldType)
+	switch underlyingType.Kind() {
+	case reflect.Slice:
+		return underlyingType.Elem().Kind() != reflect.Uint8
+	case reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
+
+// isEmptyCollection returns true iff fieldVal is a slice or map (or pointer to
+// one) without any elements.
+func isEmptyCollection(fieldVal reflect.Value) bool {
+	collectionVal, ok := dereference(fieldVal)
+	if !ok {
+		return true
+	}
+	switch collectionVal.Kind() {
+	case reflect.Slice, reflect.Map:
+		return collectionVal.Len() == 0
+	}
+	return false
+}
+
+// collectionEntry is a single element of a slice, array, or map which is
+// being bound. Exactly one of input and path is set. input is set if the
+// element is bound from a single input, and path is set if the element is a
+// struct whose fields are bound from the inputs under path.
+type collectionEntry struct {
+	key   string
+	input *Input
+	path  []pathSegment
+}
+
+// bindCollection binds the inputs under path to fieldVal, which must be a
+// settable slice, array, or map (or pointer to one). Slices and arrays may be
+// bound from repeated inputs which share the same name (e.g. "tags" or
+// "tags[]"), in which case every submitted value becomes an element, or from
+// indexed inputs (e.g. "items[0]" and "items[1]"), in which case the elements
+// are ordered by index. Maps must have string keys and are bound from inputs
+// like "attrs[key]". Elements which are structs are bound recursively (e.g.
+// from "items[0].name"). The existing value of the field is replaced, and if
+// the repeated inputs exist but none of them would be submitted, e.g. because
+// every checkbox in a group is unchecked, a slice is replaced by an empty slice.
+// It returns false if there were no inputs to bind. Any errors (including errors
+// for individual elements) are added to ctx.errs.
+func (ctx *bindContext) bindCollection(fieldVal reflect.Value, path []pathSegment, fieldName string) bool {
+	form := ctx.form
+	fieldType := fieldVal.Type()
+	collectionType := getUnderlyingFieldType(fieldType)
+	elemType := collectionType.Elem()
+	nested := isNestedStruct(elemType)
+	entries := form.collectEntries(path, nested)
+	if collectionType.Kind() == reflect.Map {
+		if len(entries) == 0 {
+			return false
+		}
+		if collectionType.Key().Kind() != reflect.String {
+			ctx.addError(fieldName, fieldType, joinPath(path), nil, fmt.Errorf("form: Don't know how to bind inputs to map with key type %s", collectionType.Key().String()))
+			return true
+		}
+		mapVal := reflect.MakeMap(collectionType)
+		for _, entry := range entries {
+			elemVal := reflect.New(elemType).Elem()
+			ctx.bindEntry(elemType, elemVal, entry, fieldName+"["+entry.key+"]")
+			mapVal.SetMapIndex(reflect.ValueOf(entry.key).Convert(collectionType.Key()), elemVal)
+		}
+		setUnderlyingFieldValue(fieldVal, mapVal)
+		return true
+	}
+	// For slices and arrays, the keys must be indexes. Sort the entries by
+	// index, skipping any which are invalid.
+	repeated := false
+	if len(entries) > 0 {
+		indexes := map[string]int{}
+		validEntries := []collectionEntry{}
+		for _, entry := range entries {
+			index, err := strconv.Atoi(entry.key)
+			if err != nil || index < 0 {
+				ctx.addError(fieldName, fieldType, joinPath(path), entry.input, fmt.Errorf("form: Invalid index %q", entry.key))
+				continue
+			}
+			indexes[entry.key] = index
+			validEntries = append(validEntries, entry)
+		}
+		entries = validEntries
+		sort.SliceStable(entries, func(i, j int) bool {
+			return indexes[entries[i].key] < indexes[entries[j].key]
+		})
+	} else if !nested {
+		// If there are no indexed inputs, fall back to repeated inputs.
+		var inputs []*Input
+		inputs, repeated = form.repeatedInputs(path)
+		for _, input := range inputs {
+			entries = append(entries, collectionEntry{input: input})
+		}
+	}
+	if len(entries) == 0 && !repeated {
+		return false
+	}
+	var collectionVal reflect.Value
+	if collectionType.Kind() == reflect.Array {
+		if len(entries) > collectionType.Len() {
+			ctx.addError(fieldName, fieldType, joinPath(path), nil, fmt.Errorf("form: Too many inputs for array of length %d", collectionType.Len()))
+			entries = entries[:collectionType.Len()]
+		}
+		collectionVal = reflect.New(collectionType).Elem()
+	} else {
+		collectionVal = reflect.MakeSlice(collectionType, len(entries), len(entries))
+	}
+	for i, entry := range entries {
+		ctx.bindEntry(elemType, collectionVal.Index(i), entry, fmt.Sprintf("%s[%d]", fieldName, i))
+	}
+	setUnderlyingFieldValue(fieldVal, collectionVal)
+	return true
+}
+
+// bindEntry binds a single collection entry to elemVal, which has the type
+// elemType. elemName is the qualified name of the element which is used in
+// errors.
+func (ctx *bindContext) bindEntry(elemType reflect.Type, elemVal reflect.Value, entry collectionEntry, elemName string) {
+	if entry.input == nil {
+		ctx.bindStruct(indirect(elemVal), entry.path, elemName)
+		return
+	}
+	if err := bindInput(elemType, elemVal, entry.input); err != nil {
+		ctx.addError(elemName, elemType, "", entry.input, err)
+	}
+}
+
+// collectEntries returns an entry for every distinct key which follows path in
+// the names of the form inputs, sorted by name. E.g., if path corresponds to
+// "attrs", the inputs "attrs[color]" and "attrs.size" result in entries with
+// the keys "color" and "size". If nested is true, only inputs with at least
+// one more segment after the key are considered and each entry gets a path
+// instead of an input.
+func (form *Form) collectEntries(path []pathSegment, nested bool) []collectionEntry {
+	entries := []collectionEntry{}
+	seen := map[string]bool{}
+	for _, name := range sortedInputNames(form.Inputs) {
+		segments := splitInputName(name)
+		if len(segments) <= len(path) || !pathMatches(path, segments[:len(path)], false) {
+			continue
+		}
+		key := segments[len(path)]
+		if key == "" || seen[key] {
+			// Empty keys (e.g. "tags[]") are handled by repeatedInputs.
+			continue
+		}
+		if nested && len(segments) > len(path)+1 {
+			seen[key] = true
+			entries = append(entries, collectionEntry{
+				key:  key,
+				path: appendPath(path, pathSegment{name: key, exact: true}),
+			})
+		} else if !nested && len(segments) == len(path)+1 {
+			seen[key] = true
+			entries = append(entries, collectionEntry{
+				key:   key,
+				input: form.Inputs[name],
+			})
+		}
+	}
+	return entries
+}
+
+// repeatedInputs returns an input for every value that the browser would
+// submit for the inputs whose name matches path, optionally followed by empty
+// brackets (e.g. "tags" or "tags[]"). Each input is a copy of the original
+// input with RawValue set to a single value. found is true iff at least one
+// input matches path, even if none of them would be submitted (e.g. a group of
+// unchecked checkboxes).
+func (form *Form) repeatedInputs(path []pathSegment) (inputs []*Input, found bool) {
+	emptyPath := appendPath(path, pathSegment{name: "", exact: true})
+	inputs = []*Input{}
+	for _, name := range sortedInputNames(form.Inputs) {
+		segments := splitInputName(name)
+		if !pathMatches(path, segments, false) && !pathMatches(emptyPath, segments, false) {
+			continue
+		}
+		found = true
+		for _, input := range form.Groups[name] {
+			for _, value := range input.Values() {
+				valueInput := *input
+				valueInput.RawValue = value
+				inputs = append(inputs, &valueInput)
+			}
+		}
+	}
+	return inputs, found
+}
+
+// indirect returns the value that fieldVal ultimately points to, allocating
+// any nil pointers along the way. If fieldVal is not a pointer, it is
+// returned as is.
+func indirect(fieldVal reflect.Value) reflect.Value {
+	for fieldVal.Kind() == reflect.Ptr {
+		if fieldVal.IsNil() {
+			fieldVal.Set(reflect.New(fieldVal.Type().Elem()))
+		}
+		fieldVal = fieldVal.Elem()
+	}
+	return fieldVal
+}
+
+// fieldTag holds the information from the form struct tag for a single field.
+type fieldTag struct {
+	// name is the input name given in the tag, if any.
+	name string
+	// skip is true iff the tag is "-".
+	skip bool
+	// omitEmpty is true iff the tag has the omitempty option.
+	omitEmpty bool
+	// required is true iff the tag has the required option.
+	required bool
+}
+
+// parseFieldTag parses the form struct tag for field. The tag consists of an
+// optional input name followed by a comma-separated list of options, similar
+// to the json struct tag in the encoding/json package.
+func parseFieldTag(field reflect.StructField) fieldTag {
+	tagValue := field.Tag.Get("form")
+	if tagValue == "-" {
+		return fieldTag{skip: true}
+	}
+	parts := strings.Split(tagValue, ",")
+	tag := fieldTag{
+		name: parts[0],
+	}
+	for _, option := range parts[1:] {
+		switch option {
+		case "omitempty":
+			tag.omitEmpty = true
+		case "required":
+			tag.required = true
+		}
+	}
+	return tag
+}
+
+// fieldSegments returns the path segments for field. If tag includes a name,
+// the segments come from the name and must match exactly. Otherwise there is a
+// single segment, the field name, which matches regardless of case.
+func fieldSegments(field reflect.StructField, tag fieldTag) []pathSegment {
+	if tag.name == "" {
+		return []pathSegment{{name: field.Name}}
+	}
+	segments := []pathSegment{}
+	for _, name := range splitInputName(tag.name) {
+		segments = append(segments, pathSegment{name: name, exact: true})
+	}
+	return segments
+}
+
+// structField is a field which is matched against inputs, either declared
+// directly in a struct or promoted from an anonymous embedded struct.
+type structField struct {
+	field reflect.StructField
+	tag   fieldTag
+	// index is the sequence of indexes which leads to the field, like the
+	// Index of a reflect.StructField returned by FieldByName.
+	index []int
+}
+
+// structFields returns the fields of typ which are matched against inputs,
+// including the fields promoted from anonymous embedded structs, in the order
+// in which they are declared. Skipped and unexported fields are left out, and
+// so are embedded pointers to unexported types, since they cannot be
+// allocated. Like in the encoding/json package, a promoted field is hidden by
+// a field with the same name at a shallower depth. If there are several fields
+// with the same name at the shallowest depth, the one with a name in its form
+// tag wins, and if that does not decide it, all of them are left out.
+func structFields(typ reflect.Type) []structField {
+	fields := []structField{}
+	collectStructFields(typ, nil, map[reflect.Type]bool{}, &fields)
+	byName := map[string][]structField{}
+	for _, sf := range fields {
+		name := sf.matchName()
+		byName[name] = append(byName[name], sf)
+	}
+	dominant := []structField{}
+	for _, sf := range fields {
+		if sf.dominates(byName[sf.matchName()]) {
+			dominant = append(dominant, sf)
+		}
+	}
+	return dominant
+}
+
+// collectStructFields appends the fields of typ to fields, descending into
+// anonymous embedded structs. index holds the indexes which lead to typ itself,
+// and embedding holds the types which are currently being descended into, so
+// that a struct which embeds itself through a pointer does not recurse forever.
+func collectStructFields(typ reflect.Type, index []int, embedding map[reflect.Type]bool, fields *[]structField) {
+	embedding[typ] = true
+	defer delete(embedding, typ)
 	for i := 0; i < typ.NumField(); i++ {
 		field := typ.Field(i)
-		for _, input := range form.Inputs {
-			if strings.EqualFold(field.Name, input.Name) {
-				// If the names match, attempt to bind the input to the field.
-				if err := bindInput(field.Type, val.FieldByName(field.Name), input); err != nil {
-					return err
-				}
-				break
+		tag := parseFieldTag(field)
+		if tag.skip {
+			continue
+		}
+		fieldIndex := append(append([]int{}, index...), i)
+		if field.Anonymous && tag.name == "" && isNestedStruct(field.Type) {
+			// The fields of an embedded struct are promoted, i.e. they are
+			// matched as if they were fields of the outer struct. This mirrors
+			// the behavior of the encoding/json package.
+			embeddedType := getUnderlyingFieldType(field.Type)
+			if field.Type.Kind() == reflect.Ptr && field.PkgPath != "" {
+				// We can't allocate a pointer to an unexported type.
+				continue
+			}
+			if !embedding[embeddedType] {
+				collectStructFields(embeddedType, fieldIndex, embedding, fields)
 			}
+			continue
 		}
+		if field.PkgPath != "" {
+			// Skip unexported fields.
+			continue
+		}
+		*fields = append(*fields, structField{field: field, tag: tag, index: fieldIndex})
 	}
-	return nil
+}
+
+// matchName returns the name which the field is matched against in lowercase,
+// so that fields which could match the same input have the same name.
+func (sf structField) matchName() string {
+	return strings.ToLower(joinPath(fieldSegments(sf.field, sf.tag)))
+}
+
+// dominates returns true iff sf wins over the other fields in rivals, which
+// all have the same name as sf (including sf itself). See structFields.
+func (sf structField) dominates(rivals []structField) bool {
+	depth := len(sf.index)
+	shallowest, tagged := 0, 0
+	for _, rival := range rivals {
+		if len(rival.index) < depth {
+			return false
+		}
+		if len(rival.index) == depth {
+			shallowest++
+			if rival.tag.name != "" {
+				tagged++
+			}
+		}
+	}
+	return shallowest == 1 || (tagged == 1 && sf.tag.name != "")
 }
 
 // getUnderlyingFieldType returns the underlying type of the given fieldType.
@@ -167,7 +722,7 @@ func bindInput(fieldType reflect.Type, fieldVal reflect.Value, input *Input) err
 		setUnderlyingFieldValue(fieldVal, reflect.ValueOf(valBool))
 		return nil
 	}
-	if underlyingType == reflect.TypeOf(time.Time{}) {
+	if underlyingType == timeType {
 		valTime, err := input.Time()
 		if err != nil {
 			return err
@@ -175,6 +730,22 @@ func bindInput(fieldType reflect.Type, fieldVal reflect.Value, input *Input) err
 		setUnderlyingFieldValue(fieldVal, reflect.ValueOf(valTime))
 		return nil
 	}
+	if underlyingType == clockType {
+		valClock, err := input.Clock()
+		if err != nil {
+			return err
+		}
+		setUnderlyingFieldValue(fieldVal, reflect.ValueOf(valClock))
+		return nil
+	}
+	if underlyingType == weekType {
+		valWeek, err := input.Week()
+		if err != nil {
+			return err
+		}
+		setUnderlyingFieldValue(fieldVal, reflect.ValueOf(valWeek))
+		return nil
+	}
 	return fmt.Errorf(
 		"form: Don't know how to bind input of type %s and value %v to struct field of type %s",
 		string(input.Type),
@@ -0,0 +1,703 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newBindTestForm returns a form with an input for every type supported by
+// Bind.
+func newBindTestForm() *Form {
+	return NewForm(
+		newTestInput("string", InputText, "foo"),
+		newTestInput("bytes", InputText, "bar"),
+		newTestInput("int", InputNumber, "4"),
+		newTestInput("int8", InputNumber, "8"),
+		newTestInput("int16", InputNumber, "15"),
+		newTestInput("int32", InputNumber, "16"),
+		newTestInput("int64", InputNumber, "23"),
+		newTestInput("uint", InputNumber, "42"),
+		newTestInput("uint8", InputNumber, "1"),
+		newTestInput("uint16", InputNumber, "2"),
+		newTestInput("uint32", InputNumber, "3"),
+		newTestInput("uint64", InputNumber, "4"),
+		newTestInput("float32", InputNumber, "39.7"),
+		newTestInput("float64", InputNumber, "12.6"),
+		newCheckedTestInput("bool", InputCheckbox, "on", true),
+		newTestInput("time", InputDateTime, "1985-12-03T23:59:34-08:00"),
+	)
+}
+
+func TestBind(t *testing.T) {
+	form := newBindTestForm()
+	target := struct {
+		String  string
+		Bytes   []byte
+		Int     int
+		Int8    int8
+		Int16   int16
+		Int32   int32
+		Int64   int64
+		Uint    uint
+		Uint8   uint8
+		Uint16  uint16
+		Uint32  uint32
+		Uint64  uint64
+		Float32 float32
+		Float64 float64
+		Bool    bool
+		Time    time.Time
+		Ignored string
+	}{}
+	if err := form.Bind(&target); err != nil {
+		t.Fatalf("Unexpected error from Bind: %s", err)
+	}
+	expectedTime := mustParseTime(time.RFC3339, "1985-12-03T23:59:34-08:00")
+	if !target.Time.Equal(expectedTime) {
+		t.Errorf("target.Time was not correct. Expected %v but got %v", expectedTime, target.Time)
+	}
+	target.Time = time.Time{}
+	expected := target
+	expected.String = "foo"
+	expected.Bytes = []byte("bar")
+	expected.Int = 4
+	expected.Int8 = 8
+	expected.Int16 = 15
+	expected.Int32 = 16
+	expected.Int64 = 23
+	expected.Uint = 42
+	expected.Uint8 = 1
+	expected.Uint16 = 2
+	expected.Uint32 = 3
+	expected.Uint64 = 4
+	expected.Float32 = 39.7
+	expected.Float64 = 12.6
+	expected.Bool = true
+	expected.Ignored = ""
+	if !reflect.DeepEqual(target, expected) {
+		t.Errorf("Bind did not produce the correct result.\nExpected: %+v\nBut got:  %+v", expected, target)
+	}
+}
+
+func TestBindWithPointers(t *testing.T) {
+	form := newBindTestForm()
+	target := struct {
+		String  *string
+		Bytes   *[]byte
+		Int     *int
+		Int8    *int8
+		Uint64  *uint64
+		Float32 *float32
+		Bool    *bool
+		Time    *time.Time
+	}{}
+	if err := form.Bind(&target); err != nil {
+		t.Fatalf("Unexpected error from Bind: %s", err)
+	}
+	if target.String == nil || *target.String != "foo" {
+		t.Errorf("target.String was not correct: %v", target.String)
+	}
+	if target.Bytes == nil || string(*target.Bytes) != "bar" {
+		t.Errorf("target.Bytes was not correct: %v", target.Bytes)
+	}
+	if target.Int == nil || *target.Int != 4 {
+		t.Errorf("target.Int was not correct: %v", target.Int)
+	}
+	if target.Int8 == nil || *target.Int8 != 8 {
+		t.Errorf("target.Int8 was not correct: %v", target.Int8)
+	}
+	if target.Uint64 == nil || *target.Uint64 != 4 {
+		t.Errorf("target.Uint64 was not correct: %v", target.Uint64)
+	}
+	if target.Float32 == nil || *target.Float32 != 39.7 {
+		t.Errorf("target.Float32 was not correct: %v", target.Float32)
+	}
+	if target.Bool == nil || *target.Bool != true {
+		t.Errorf("target.Bool was not correct: %v", target.Bool)
+	}
+	expectedTime := mustParseTime(time.RFC3339, "1985-12-03T23:59:34-08:00")
+	if target.Time == nil || !target.Time.Equal(expectedTime) {
+		t.Errorf("target.Time was not correct: %v", target.Time)
+	}
+}
+
+func TestBindClockAndWeek(t *testing.T) {
+	form := NewForm(
+		newTestInput("opens", InputTime, "08:30"),
+		newTestInput("closes", InputTime, "17:45:30"),
+		newTestInput("week", InputWeek, "2015-W23"),
+		newTestInput("month", InputMonth, "2015-06"),
+	)
+	target := struct {
+		Opens  Clock
+		Closes *Clock
+		Week   Week
+		Month  time.Time
+	}{}
+	if err := form.Bind(&target); err != nil {
+		t.Fatalf("Unexpected error from Bind: %s", err)
+	}
+	if expected := (Clock{Hour: 8, Minute: 30}); target.Opens != expected {
+		t.Errorf("target.Opens was not correct. Expected %+v but got %+v", expected, target.Opens)
+	}
+	if expected := (Clock{Hour: 17, Minute: 45, Second: 30}); target.Closes == nil || *target.Closes != expected {
+		t.Errorf("target.Closes was not correct: %v", target.Closes)
+	}
+	if expected := (Week{Year: 2015, Week: 23}); target.Week != expected {
+		t.Errorf("target.Week was not correct. Expected %+v but got %+v", expected, target.Week)
+	}
+	if expected := mustParseTime("2006-01-02", "2015-06-01"); !target.Month.Equal(expected) {
+		t.Errorf("target.Month was not correct. Expected %v but got %v", expected, target.Month)
+	}
+	// Invalid values should result in user-facing messages.
+	form = NewForm(
+		newTestInput("opens", InputTime, "8:30"),
+		newTestInput("week", InputWeek, "2015-W54"),
+	)
+	err := form.Bind(&target)
+	errs, ok := err.(BindErrors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("Expected two BindErrors but got: %v", err)
+	}
+	expectedMessages := []string{"opens must be a valid time.", "week must be a valid week."}
+	for i, expected := range expectedMessages {
+		if _, got := errs[i].rule(errs[i].InputName); got != expected {
+			t.Errorf("Incorrect message. Expected %q but got %q", expected, got)
+		}
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	form := NewForm(newTestInput("int", InputNumber, "foo"))
+	notStruct := 0
+	testCases := []struct {
+		v    interface{}
+		desc string
+	}{
+		{struct{}{}, "non-pointer"},
+		{&notStruct, "pointer to non-struct"},
+		{(*struct{})(nil), "nil pointer"},
+		{&struct{ Int int }{}, "invalid int"},
+		{&struct{ Int chan int }{}, "unsupported field type"},
+	}
+	for _, tc := range testCases {
+		if err := form.Bind(tc.v); err == nil {
+			t.Errorf("Expected an error from Bind for %s but got none", tc.desc)
+		}
+	}
+}
+
+// customBinder implements Binder.
+type customBinder struct {
+	Int    int
+	String string
+}
+
+// BindForm implements the BindForm method of Binder. Instead of binding the
+// fields directly, we add 1 to the Int field and prepend a "_" to the String
+// field.
+func (b *customBinder) BindForm(form *Form) error {
+	valInt, err := form.GetInt("int")
+	if err != nil {
+		return err
+	}
+	b.Int = valInt + 1
+	valString, err := form.GetString("string")
+	if err != nil {
+		return err
+	}
+	b.String = "_" + valString
+	return nil
+}
+
+func TestBinder(t *testing.T) {
+	form := NewForm(
+		newTestInput("string", InputText, "foo"),
+		newTestInput("int", InputNumber, "42"),
+	)
+	binder := &customBinder{}
+	if err := form.Bind(binder); err != nil {
+		t.Fatalf("Unexpected error from Bind: %s", err)
+	}
+	if binder.String != "_foo" {
+		t.Errorf("binder.String was not correct. Expected %q but got %q", "_foo", binder.String)
+	}
+	if binder.Int != 43 {
+		t.Errorf("binder.Int was not correct. Expected 43 but got %d", binder.Int)
+	}
+}
+
+// fullName is a custom type which implements InputBinder.
+type fullName struct {
+	First string
+	Last  string
+}
+
+// BindInput implements the BindInput method of InputBinder. We split the input
+// into a first name and last name and assign each field manually.
+func (name *fullName) BindInput(input *Input) error {
+	names := strings.Split(input.RawValue, " ")
+	name.First = names[0]
+	name.Last = names[1]
+	return nil
+}
+
+func TestInputBinder(t *testing.T) {
+	form := NewForm(newTestInput("name", InputText, "Foo Bar"))
+	target := struct {
+		Name fullName
+	}{}
+	if err := form.Bind(&target); err != nil {
+		t.Fatalf("Unexpected error from Bind: %s", err)
+	}
+	expected := fullName{First: "Foo", Last: "Bar"}
+	if target.Name != expected {
+		t.Errorf("target.Name was not correct. Expected %+v but got %+v", expected, target.Name)
+	}
+	// Check that a nil pointer field which implements InputBinder is
+	// initialized.
+	ptrTarget := struct {
+		Name *fullName
+	}{}
+	if err := form.Bind(&ptrTarget); err != nil {
+		t.Fatalf("Unexpected error from Bind: %s", err)
+	}
+	if ptrTarget.Name == nil || *ptrTarget.Name != expected {
+		t.Errorf("ptrTarget.Name was not correct. Expected %+v but got %+v", expected, ptrTarget.Name)
+	}
+}
+
+func TestBindTags(t *testing.T) {
+	form := NewForm(
+		newTestInput("first-name", InputText, "Foo"),
+		newTestInput("user_email", InputEmail, "foo@example.com"),
+		newTestInput("secret", InputText, "shh"),
+		newTestInput("age", InputNumber, ""),
+		newTestInput("Nickname", InputText, "foobar"),
+	)
+	target := struct {
+		FirstName string `form:"first-name"`
+		Email     string `form:"user_email,required"`
+		Secret    string `form:"-"`
+		Age       int    `form:",omitempty"`
+		Nickname  string
+		private   string
+	}{
+		Age:     30,
+		private: "unchanged",
+	}
+	if err := form.Bind(&target); err != nil {
+		t.Fatalf("Unexpected error from Bind: %s", err)
+	}
+	if target.FirstName != "Foo" {
+		t.Errorf("target.FirstName was not correct. Expected %q but got %q", "Foo", target.FirstName)
+	}
+	if target.Email != "foo@example.com" {
+		t.Errorf("target.Email was not correct. Expected %q but got %q", "foo@example.com", target.Email)
+	}
+	if target.Secret != "" {
+		t.Errorf("Expected target.Secret to be skipped but got %q", target.Secret)
+	}
+	if target.Age != 30 {
+		t.Errorf("Expected target.Age to be untouched because of omitempty but got %d", target.Age)
+	}
+	if target.Nickname != "foobar" {
+		t.Errorf("target.Nickname was not correct. Expected %q but got %q", "foobar", target.Nickname)
+	}
+	if target.private != "unchanged" {
+		t.Errorf("Expected unexported field to be untouched but got %q", target.private)
+	}
+
+	// Check that the required option causes an error for missing and empty
+	// inputs.
+	missing := struct {
+		Phone string `form:"phone,required"`
+	}{}
+	if err := form.Bind(&missing); err == nil {
+		t.Error("Expected an error for a missing required input but got none")
+	}
+	empty := struct {
+		Age int `form:"age,required"`
+	}{}
+	if err := form.Bind(&empty); err == nil {
+		t.Error("Expected an error for an empty required input but got none")
+	}
+	// The input name for a missing input is the name it would usually have.
+	untagged := struct {
+		Phone   string `form:",required"`
+		Billing struct {
+			ZipCode string `form:",required"`
+			Country string `form:"Country,required"`
+		}
+	}{}
+	err := form.Bind(&untagged)
+	bindErrs, ok := err.(BindErrors)
+	if !ok || len(bindErrs) != 3 {
+		t.Fatalf("Expected three BindErrors for the missing required inputs but got: %v", err)
+	}
+	for i, expected := range []string{"phone", "billing.zipcode", "billing.Country"} {
+		if bindErrs[i].InputName != expected {
+			t.Errorf("Expected InputName to be %q but got %q", expected, bindErrs[i].InputName)
+		}
+	}
+}
+
+func TestBindNonASCIINames(t *testing.T) {
+	form := NewForm(
+		newTestInput("prénom", InputText, "Zoë"),
+		newTestInput("ADRESSE[RUE]", InputText, "Rue de l'Église"),
+	)
+	target := struct {
+		Prénom  string
+		Adresse struct {
+			Rue string
+		}
+	}{}
+	if err := form.Bind(&target); err != nil {
+		t.Fatalf("Unexpected error from Bind: %s", err)
+	}
+	if target.Prénom != "Zoë" {
+		t.Errorf("target.Prénom was not correct. Expected %q but got %q", "Zoë", target.Prénom)
+	}
+	if target.Adresse.Rue != "Rue de l'Église" {
+		t.Errorf("target.Adresse.Rue was not correct. Expected %q but got %q", "Rue de l'Église", target.Adresse.Rue)
+	}
+}
+
+type address struct {
+	Street string
+	City   string
+}
+
+type Timestamps struct {
+	CreatedAt time.Time `form:"created"`
+}
+
+type embeddedPtr struct {
+	Note string
+}
+
+func TestBindNested(t *testing.T) {
+	form := NewForm(
+		newTestInput("name", InputText, "Foo"),
+		newTestInput("billing.street", InputText, "1 Main St"),
+		newTestInput("billing[city]", InputText, "Springfield"),
+		newTestInput("shipping_address[street]", InputText, "2 Side St"),
+		newTestInput("created", InputDate, "2015-06-01"),
+		newTestInput("zip", InputText, "12345"),
+	)
+	target := struct {
+		Name     string
+		Billing  address
+		Shipping *address `form:"shipping_address"`
+		Other    *address
+		Timestamps
+		*embeddedPtr
+		Zip struct {
+			Code string
+		} `form:"-"`
+	}{}
+	if err := form.Bind(&target); err != nil {
+		t.Fatalf("Unexpected error from Bind: %s", err)
+	}
+	if target.Name != "Foo" {
+		t.Errorf("target.Name was not correct. Expected %q but got %q", "Foo", target.Name)
+	}
+	expectedBilling := address{Street: "1 Main St", City: "Springfield"}
+	if target.Billing != expectedBilling {
+		t.Errorf("target.Billing was not correct. Expected %+v but got %+v", expectedBilling, target.Billing)
+	}
+	expectedShipping := address{Street: "2 Side St"}
+	if target.Shipping == nil || *target.Shipping != expectedShipping {
+		t.Errorf("target.Shipping was not correct. Expected %+v but got %+v", expectedShipping, target.Shipping)
+	}
+	if target.Other != nil {
+		t.Errorf("Expected target.Other to remain nil because there were no inputs but got %+v", target.Other)
+	}
+	expectedCreated := mustParseTime("2006-01-02", "2015-06-01")
+	if !target.CreatedAt.Equal(expectedCreated) {
+		t.Errorf("target.CreatedAt was not correct. Expected %v but got %v", expectedCreated, target.CreatedAt)
+	}
+	if target.embeddedPtr != nil {
+		t.Errorf("Expected unexported embedded pointer to remain nil but got %+v", target.embeddedPtr)
+	}
+	if target.Zip.Code != "" {
+		t.Errorf("Expected target.Zip to be skipped but got %+v", target.Zip)
+	}
+}
+
+type contact struct {
+	Name  string
+	Email string
+	Phone string
+}
+
+type profile struct {
+	contact
+	Email string
+}
+
+type social struct {
+	Phone   string
+	Website string `form:"website"`
+}
+
+type company struct {
+	Phone   string
+	Website string
+}
+
+// recursive embeds itself through a pointer.
+type recursive struct {
+	*recursive
+	Title string
+}
+
+func TestBindEmbeddedConflicts(t *testing.T) {
+	form := NewForm(
+		newTestInput("name", InputText, "Foo"),
+		newTestInput("email", InputText, "foo@example.com"),
+		newTestInput("phone", InputText, "555-1234"),
+		newTestInput("website", InputURL, "https://example.com"),
+		newTestInput("title", InputText, "Dr."),
+	)
+	target := struct {
+		Name string
+		profile
+		social
+		company
+		recursive
+	}{}
+	if err := form.Bind(&target); err != nil {
+		t.Fatalf("Unexpected error from Bind: %s", err)
+	}
+	// The field of the outer struct hides the promoted field.
+	if target.Name != "Foo" {
+		t.Errorf("target.Name was not correct. Expected %q but got %q", "Foo", target.Name)
+	}
+	if target.profile.contact.Name != "" {
+		t.Errorf("Expected the hidden field contact.Name to be left alone but got %q", target.profile.contact.Name)
+	}
+	// A field promoted from a shallower depth hides the deeper one.
+	if target.profile.Email != "foo@example.com" {
+		t.Errorf("target.profile.Email was not correct. Expected %q but got %q", "foo@example.com", target.profile.Email)
+	}
+	if target.profile.contact.Email != "" {
+		t.Errorf("Expected the hidden field contact.Email to be left alone but got %q", target.profile.contact.Email)
+	}
+	if target.social.Phone != "" || target.company.Phone != "" {
+		t.Errorf("Expected both conflicting Phone fields at the same depth to be left alone but got %q and %q", target.social.Phone, target.company.Phone)
+	}
+	// A tagged field wins over an untagged field at the same depth.
+	if target.social.Website != "https://example.com" {
+		t.Errorf("target.social.Website was not correct. Expected %q but got %q", "https://example.com", target.social.Website)
+	}
+	if target.company.Website != "" {
+		t.Errorf("Expected the untagged field company.Website to be left alone but got %q", target.company.Website)
+	}
+	if target.recursive.Title != "Dr." {
+		t.Errorf("target.recursive.Title was not correct. Expected %q but got %q", "Dr.", target.recursive.Title)
+	}
+}
+
+type lineItem struct {
+	Name     string
+	Quantity int
+}
+
+func TestBindCollections(t *testing.T) {
+	form := NewForm(
+		newTestInput("tags[]", InputText, "foo"),
+		newTestInput("tags[]", InputText, "bar"),
+		newCheckedTestInput("scores", InputCheckbox, "1", true),
+		newCheckedTestInput("scores", InputCheckbox, "2", false),
+		newCheckedTestInput("scores", InputCheckbox, "3", true),
+		NewInput(testElement{name: "sizes", typ: InputSelectMultiple, value: "s", selectedValues: []string{"s", "l"}}),
+		newTestInput("ids[1]", InputNumber, "20"),
+		newTestInput("ids[0]", InputNumber, "10"),
+		newTestInput("ids[10]", InputNumber, "30"),
+		newTestInput("coords[0]", InputNumber, "1.5"),
+		newTestInput("coords[1]", InputNumber, "-2.5"),
+		newTestInput("attrs[color]", InputText, "red"),
+		newTestInput("attrs.size", InputText, "large"),
+		newTestInput("items[0].name", InputText, "apple"),
+		newTestInput("items[0].quantity", InputNumber, "3"),
+		newTestInput("items[1][name]", InputText, "pear"),
+		newTestInput("items[1][quantity]", InputNumber, "5"),
+		newTestInput("rows[a].name", InputText, "first"),
+		newCheckedTestInput("colors", InputCheckbox, "red", false),
+		newCheckedTestInput("colors", InputCheckbox, "blue", false),
+	)
+	target := struct {
+		Tags   []string
+		Scores []int
+		Sizes  *[]string
+		IDs    []uint
+		Coords [2]float64
+		Attrs  map[string]string
+		Items  []lineItem
+		Rows   map[string]*lineItem
+		Colors []string
+		Empty  []string
+	}{
+		Colors: []string{"green"},
+		Empty:  []string{"untouched"},
+	}
+	if err := form.Bind(&target); err != nil {
+		t.Fatalf("Unexpected error from Bind: %s", err)
+	}
+	if expected := []string{"foo", "bar"}; !reflect.DeepEqual(target.Tags, expected) {
+		t.Errorf("target.Tags was not correct. Expected %v but got %v", expected, target.Tags)
+	}
+	if expected := []int{1, 3}; !reflect.DeepEqual(target.Scores, expected) {
+		t.Errorf("target.Scores was not correct. Expected %v but got %v", expected, target.Scores)
+	}
+	if expected := []string{"s", "l"}; target.Sizes == nil || !reflect.DeepEqual(*target.Sizes, expected) {
+		t.Errorf("target.Sizes was not correct. Expected %v but got %v", expected, target.Sizes)
+	}
+	if expected := []uint{10, 20, 30}; !reflect.DeepEqual(target.IDs, expected) {
+		t.Errorf("target.IDs was not correct. Expected %v but got %v", expected, target.IDs)
+	}
+	if expected := [2]float64{1.5, -2.5}; target.Coords != expected {
+		t.Errorf("target.Coords was not correct. Expected %v but got %v", expected, target.Coords)
+	}
+	if expected := map[string]string{"color": "red", "size": "large"}; !reflect.DeepEqual(target.Attrs, expected) {
+		t.Errorf("target.Attrs was not correct. Expected %v but got %v", expected, target.Attrs)
+	}
+	if expected := []lineItem{{"apple", 3}, {"pear", 5}}; !reflect.DeepEqual(target.Items, expected) {
+		t.Errorf("target.Items was not correct. Expected %v but got %v", expected, target.Items)
+	}
+	if row := target.Rows["a"]; row == nil || row.Name != "first" {
+		t.Errorf("target.Rows was not correct. Got %v", target.Rows)
+	}
+	if target.Colors == nil || len(target.Colors) != 0 {
+		t.Errorf("Expected target.Colors to be an empty slice because no checkbox was checked but got %#v", target.Colors)
+	}
+	if expected := []string{"untouched"}; !reflect.DeepEqual(target.Empty, expected) {
+		t.Errorf("Expected target.Empty to be untouched but got %v", target.Empty)
+	}
+
+	// A required group in which nothing is checked is empty.
+	required := struct {
+		Colors []string `form:"colors,required"`
+	}{}
+	err := form.Bind(&required)
+	if bindErrs, ok := err.(BindErrors); !ok || len(bindErrs) != 1 || bindErrs[0].Err != errRequiredEmpty {
+		t.Errorf("Expected a single error for the empty required group but got: %v", err)
+	}
+}
+
+func TestBindCollectionErrors(t *testing.T) {
+	testCases := []struct {
+		input *Input
+		v     interface{}
+		desc  string
+	}{
+		{newTestInput("ids[x]", InputNumber, "1"), &struct{ IDs []int }{}, "non-integer index"},
+		{newTestInput("ids[-1]", InputNumber, "1"), &struct{ IDs []int }{}, "negative index"},
+		{newTestInput("ids[0]", InputNumber, "foo"), &struct{ IDs []int }{}, "invalid element"},
+		{newTestInput("ids[2]", InputNumber, "1"), &struct{ IDs [0]int }{}, "array too short"},
+		{newTestInput("ids[0]", InputNumber, "1"), &struct{ IDs map[int]int }{}, "non-string map key"},
+	}
+	for _, tc := range testCases {
+		if err := NewForm(tc.input).Bind(tc.v); err == nil {
+			t.Errorf("Expected an error from Bind for %s but got none", tc.desc)
+		}
+	}
+}
+
+func TestBindAggregatesErrors(t *testing.T) {
+	form := NewForm(
+		newTestInput("name", InputText, "Foo"),
+		newTestInput("age", InputNumber, "old"),
+		newTestInput("height", InputNumber, "tall"),
+		newTestInput("billing.zip", InputNumber, "abc"),
+		newTestInput("scores[0]", InputNumber, "1"),
+		newTestInput("scores[1]", InputNumber, "two"),
+	)
+	target := struct {
+		Name    string
+		Age     int
+		Height  float64
+		Billing struct {
+			Zip int
+		}
+		Scores []int
+		Email  string `form:"email,required"`
+	}{}
+	err := form.Bind(&target)
+	bindErrs, ok := err.(BindErrors)
+	if !ok {
+		t.Fatalf("Expected BindErrors but got %T: %v", err, err)
+	}
+	// The valid fields should still be bound.
+	if target.Name != "Foo" {
+		t.Errorf("Expected target.Name to be bound despite errors but got %q", target.Name)
+	}
+	if expected := []int{1, 0}; !reflect.DeepEqual(target.Scores, expected) {
+		t.Errorf("Expected target.Scores to be %v but got %v", expected, target.Scores)
+	}
+	expected := []struct {
+		field     string
+		inputName string
+		inputType InputType
+		rawValue  string
+	}{
+		{"Age", "age", InputNumber, "old"},
+		{"Height", "height", InputNumber, "tall"},
+		{"Billing.Zip", "billing.zip", InputNumber, "abc"},
+		{"Scores[1]", "scores[1]", InputNumber, "two"},
+		{"Email", "email", "", ""},
+	}
+	if len(bindErrs) != len(expected) {
+		t.Fatalf("Expected %d errors but got %d: %v", len(expected), len(bindErrs), bindErrs)
+	}
+	for i, exp := range expected {
+		got := bindErrs[i]
+		if got.Field != exp.field || got.InputName != exp.inputName || got.InputType != exp.inputType || got.RawValue != exp.rawValue {
+			t.Errorf("Error %d was not correct. Expected %+v but got %+v", i, exp, got)
+		}
+		if got.Err == nil {
+			t.Errorf("Error %d did not have an underlying error", i)
+		}
+	}
+
+	// Check that the errors can be merged into the form errors.
+	form.AddBindErrors(bindErrs)
+	expectedMessages := []string{
+		"age must be an integer.",
+		"height must be a number.",
+		"billing.zip must be an integer.",
+		"scores[1] must be an integer.",
+		"email is required.",
+	}
+	if len(form.Errors) != len(expectedMessages) {
+		t.Fatalf("Expected %d form errors but got %d", len(expectedMessages), len(form.Errors))
+	}
+	for i, msg := range expectedMessages {
+		valErr, ok := form.Errors[i].(*ValidationError)
+		if !ok {
+			t.Errorf("Expected form error %d to be a *ValidationError but got %T", i, form.Errors[i])
+			continue
+		}
+		if valErr.Error() != msg {
+			t.Errorf("Form error %d was not correct. Expected %q but got %q", i, msg, valErr.Error())
+		}
+	}
+	if form.Errors[0].(*ValidationError).Input != form.Inputs["age"] {
+		t.Error("Expected the ValidationError to reference the input")
+	}
+	expectedCodes := []string{RuleInteger, RuleNumber, RuleInteger, RuleInteger, RuleRequired}
+	for i, code := range expectedCodes {
+		if got := form.Errors[i].(*ValidationError).Code; got != code {
+			t.Errorf("Form error %d had the wrong code. Expected %q but got %q", i, code, got)
+		}
+	}
+}
@@ -0,0 +1,339 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// ValidateConstraints validates every input in the form against the html5
+// constraint attributes of its element: required, min, max, step, minlength,
+// maxlength, and pattern. It adds a ValidationError to form.Errors for each
+// violated constraint, just like the methods of InputValidation, so it can be
+// combined freely with other validations. Only attributes which are present on
+// the element (see Element.GetAttribute) are checked, and attributes with
+// invalid values are ignored, just like the browser ignores them.
+//
+// The attributes are applied to the same input types as in the browser. The
+// required attribute is satisfied for a checkbox if it is checked, and for a
+// group of radio buttons if any of them is checked. The minlength, maxlength,
+// and pattern attributes apply to text-like inputs, and pattern must match the
+// entire value. The min and max attributes apply to number and range inputs,
+// which are compared as numbers, and to date, month, week, time, and
+// datetime-local inputs, which are compared as times (see Input.Time). For time
+// inputs, a min which is after max allows a range which wraps around midnight.
+// The step attribute is only checked for number and range inputs. Inputs which
+// are disabled or readonly are skipped, as are hidden inputs and buttons.
+//
+// Inputs created by ParseValues or ParseRequest have no attributes, so
+// ValidateConstraints is mostly useful for forms created by Parse.
+func (form *Form) ValidateConstraints() {
+	for _, name := range sortedInputNames(form.Inputs) {
+		requiredChecked := false
+		for _, input := range form.Groups[name] {
+			if input.El == nil || !isConstraintValidated(input) {
+				continue
+			}
+			val := &InputValidation{
+				Input:     input,
+				Form:      form,
+				InputName: name,
+			}
+			switch input.Type {
+			case InputCheckbox, InputRadio:
+				// Only report one required error for a group of checkboxes or
+				// radio buttons.
+				if !requiredChecked && input.El.HasAttribute("required") {
+					requiredChecked = true
+					val.requireChecked(form.Groups[name])
+				}
+				continue
+			}
+			val.validateConstraints()
+		}
+	}
+}
+
+// isConstraintValidated returns true iff the browser would apply constraint
+// validation to the input.
+func isConstraintValidated(input *Input) bool {
+	switch input.Type {
+	case InputHidden, InputButton, InputSubmit, InputReset, InputImage:
+		return false
+	}
+	return !input.El.HasAttribute("disabled") && !input.El.HasAttribute("readonly")
+}
+
+// requireChecked adds a required error if the input is a checkbox which is not
+// checked, or if it is a radio button and none of the radio buttons in group
+// are checked.
+func (val *InputValidation) requireChecked(group []*Input) {
+	defer val.localize()()
+	if val.Input.Type == InputCheckbox {
+		if !val.Input.Checked {
+			val.addError(rule{code: RuleRequired}, defaultFormats[RuleRequired], val.label())
+		}
+		return
+	}
+	for _, input := range group {
+		if input.Type == InputRadio && input.Checked {
+			return
+		}
+	}
+	val.addError(rule{code: RuleRequired}, defaultFormats[RuleRequired], val.label())
+}
+
+// validateConstraints checks the constraint attributes of a single input which
+// is not a checkbox or radio button.
+func (val *InputValidation) validateConstraints() {
+	defer val.localize()()
+	el := val.Input.El
+	if el.HasAttribute("required") && val.Input.Type != InputRange && val.Input.Type != InputColor {
+		val.Required()
+	}
+	switch val.Input.Type {
+	case InputDefault, InputText, InputSearch, InputURL, InputTel, InputEmail, InputPassword, InputTextArea:
+		if limit, ok := lengthAttribute(el, "minlength"); ok {
+			val.MinLength(limit)
+		}
+		if limit, ok := lengthAttribute(el, "maxlength"); ok {
+			val.MaxLength(limit)
+		}
+		if val.Input.Type != InputTextArea && el.HasAttribute("pattern") {
+			// Like the browser, ignore patterns which are not valid.
+			if pattern, err := regexp.Compile("^(?:" + el.GetAttribute("pattern") + ")$"); err == nil {
+				r := rule{RulePattern, Params{"pattern": el.GetAttribute("pattern")}}
+				val.validateString(r, pattern.MatchString, defaultFormats[RulePattern], val.label())
+			}
+		}
+	case InputNumber, InputRange:
+		val.validateNumberConstraints()
+	case InputDate, InputMonth, InputWeek, InputTime, InputDateTimeLocal:
+		val.validateTimeConstraints()
+	}
+}
+
+// lengthAttribute returns the value of the minlength or maxlength attribute
+// and true, or false if the attribute is missing or not a valid non-negative
+// integer.
+func lengthAttribute(el Element, name string) (int, bool) {
+	if !el.HasAttribute(name) {
+		return 0, false
+	}
+	limit, err := strconv.Atoi(el.GetAttribute(name))
+	if err != nil || limit < 0 {
+		return 0, false
+	}
+	return limit, true
+}
+
+// numberAttribute returns the value of the attribute as a float64 and true, or
+// false if the attribute is missing or not a valid number.
+func numberAttribute(el Element, name string) (float64, bool) {
+	if !el.HasAttribute(name) {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(el.GetAttribute(name), 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
+
+// validateNumberConstraints checks the min, max, and step attributes of a
+// number or range input.
+func (val *InputValidation) validateNumberConstraints() {
+	// Only report a single error if the value is not a number.
+	if val.Input.RawValue == "" {
+		return
+	}
+	if _, err := val.Input.Float(); err != nil {
+		val.IsFloat()
+		return
+	}
+	el := val.Input.El
+	min, hasMin := numberAttribute(el, "min")
+	if hasMin {
+		r := rule{RuleMin, Params{"limit": el.GetAttribute("min")}}
+		val.validateFloat(r, greaterOrEqualFloatFunc(min), defaultFormats[RuleMin], val.label(), el.GetAttribute("min"))
+	}
+	if max, ok := numberAttribute(el, "max"); ok {
+		r := rule{RuleMax, Params{"limit": el.GetAttribute("max")}}
+		val.validateFloat(r, lessOrEqualFloatFunc(max), defaultFormats[RuleMax], val.label(), el.GetAttribute("max"))
+	}
+	// The step is relative to min if it is present, and 0 otherwise. A step of
+	// "any" (or any other value which is not a positive number) means that any
+	// value is allowed.
+	step, ok := numberAttribute(el, "step")
+	if !ok || step <= 0 {
+		return
+	}
+	if !hasMin || min == 0 {
+		val.StepFloatf(step, 0, defaultFormats[RuleStep], val.label(), el.GetAttribute("step"))
+		return
+	}
+	val.StepFloatf(step, min, defaultFormats[RuleStep+baseVariant], val.label(), el.GetAttribute("min"), el.GetAttribute("step"))
+}
+
+// validateTimeConstraints checks the min and max attributes of a date, month,
+// week, time, or datetime-local input.
+func (val *InputValidation) validateTimeConstraints() {
+	// Only report a single error if the value is not a valid time.
+	if val.Input.RawValue == "" {
+		return
+	}
+	if _, err := val.Input.Time(); err != nil {
+		val.IsTime()
+		return
+	}
+	el := val.Input.El
+	min, hasMin := val.timeAttribute("min")
+	max, hasMax := val.timeAttribute("max")
+	if val.Input.Type == InputTime && hasMin && hasMax && min.After(max) {
+		// A reversed range wraps around midnight, e.g. from 22:00 to 06:00.
+		r := rule{RuleRange, Params{"min": el.GetAttribute("min"), "max": el.GetAttribute("max")}}
+		val.validateTime(r, func(value time.Time) bool {
+			return !value.Before(min) || !value.After(max)
+		}, defaultFormats[RuleRange], val.label(), el.GetAttribute("min"), el.GetAttribute("max"))
+		return
+	}
+	if hasMin {
+		r := rule{RuleMin, Params{"limit": el.GetAttribute("min")}}
+		val.validateTime(r, func(value time.Time) bool {
+			return !value.Before(min)
+		}, defaultFormats[RuleMin+timeVariant], val.label(), el.GetAttribute("min"))
+	}
+	if hasMax {
+		r := rule{RuleMax, Params{"limit": el.GetAttribute("max")}}
+		val.validateTime(r, func(value time.Time) bool {
+			return !value.After(max)
+		}, defaultFormats[RuleMax+timeVariant], val.label(), el.GetAttribute("max"))
+	}
+}
+
+// timeAttribute returns the value of the attribute converted to a time.Time
+// in the same way as the value of the input, and true, or false if the
+// attribute is missing or not valid for the type of the input.
+func (val *InputValidation) timeAttribute(name string) (time.Time, bool) {
+	if !val.Input.El.HasAttribute(name) {
+		return time.Time{}, false
+	}
+	attrInput := Input{
+		RawValue: val.Input.El.GetAttribute(name),
+		Type:     val.Input.Type,
+	}
+	t, err := attrInput.Time()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// validityState holds the flags of a ValidityState object from the browser's
+// Constraint Validation API, except for customError, which is set by
+// setCustomValidity rather than by a constraint.
+type validityState struct {
+	valueMissing    bool
+	typeMismatch    bool
+	patternMismatch bool
+	tooLong         bool
+	tooShort        bool
+	rangeUnderflow  bool
+	rangeOverflow   bool
+	stepMismatch    bool
+	badInput        bool
+}
+
+// validityRules returns the rules which the input violates according to the
+// flags in state. The rules have the same codes and parameters as the errors
+// added by ValidateConstraints, so both kinds of errors can be handled in the
+// same way.
+func validityRules(input *Input, state validityState) []rule {
+	rules := []rule{}
+	el := input.El
+	if state.valueMissing {
+		rules = append(rules, rule{code: RuleRequired})
+	}
+	if state.badInput {
+		switch {
+		case input.Type == InputNumber || input.Type == InputRange:
+			rules = append(rules, rule{code: RuleNumber})
+		case isTimeInput(input.Type):
+			rules = append(rules, rule{code: RuleTime})
+		default:
+			rules = append(rules, rule{code: RuleInvalid})
+		}
+	}
+	if state.typeMismatch {
+		switch input.Type {
+		case InputEmail:
+			rules = append(rules, rule{code: RuleEmail})
+		case InputURL:
+			rules = append(rules, rule{code: RuleURL})
+		default:
+			rules = append(rules, rule{code: RuleInvalid})
+		}
+	}
+	if state.tooShort {
+		limit, _ := lengthAttribute(el, "minlength")
+		rules = append(rules, rule{RuleMinLength, Params{"limit": limit}})
+	}
+	if state.tooLong {
+		limit, _ := lengthAttribute(el, "maxlength")
+		rules = append(rules, rule{RuleMaxLength, Params{"limit": limit}})
+	}
+	if state.patternMismatch {
+		rules = append(rules, rule{RulePattern, Params{"pattern": el.GetAttribute("pattern")}})
+	}
+	if state.rangeUnderflow && state.rangeOverflow {
+		// Only a time input with a range which wraps around midnight can be
+		// both before min and after max.
+		rules = append(rules, rule{RuleRange, Params{"min": el.GetAttribute("min"), "max": el.GetAttribute("max")}})
+	} else if state.rangeUnderflow {
+		rules = append(rules, rule{RuleMin, Params{"limit": el.GetAttribute("min")}})
+	} else if state.rangeOverflow {
+		rules = append(rules, rule{RuleMax, Params{"limit": el.GetAttribute("max")}})
+	}
+	if state.stepMismatch {
+		step, _ := numberAttribute(el, "step")
+		base, _ := numberAttribute(el, "min")
+		rules = append(rules, rule{RuleStep, Params{"step": step, "base": base}})
+	}
+	return rules
+}
+
+// validityMessage returns the default message for a rule returned by
+// validityRules, i.e. the message which ValidateConstraints or the built-in
+// validation with the same code would use.
+func (val *InputValidation) validityMessage(r rule) string {
+	key := r.code
+	args := []interface{}{val.label()}
+	switch r.code {
+	case RuleMinLength, RuleMaxLength:
+		args = append(args, r.params["limit"])
+	case RuleMin, RuleMax:
+		if isTimeInput(val.Input.Type) {
+			key += timeVariant
+		}
+		args = append(args, r.params["limit"])
+	case RuleRange:
+		args = append(args, r.params["min"], r.params["max"])
+	case RuleStep:
+		if r.params["base"] != 0.0 {
+			key += baseVariant
+			args = append(args, val.Input.El.GetAttribute("min"))
+		}
+		args = append(args, val.Input.El.GetAttribute("step"))
+	}
+	format, found := defaultFormats[key]
+	if !found {
+		return fmt.Sprintf(defaultFormats[RuleInvalid], val.label())
+	}
+	return fmt.Sprintf(format, args...)
+}
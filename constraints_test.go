@@ -0,0 +1,174 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newConstrainedTestInput creates an Input backed by a testElement with the
+// given attributes.
+func newConstrainedTestInput(typ InputType, value string, attrs map[string]string) *Input {
+	return NewInput(testElement{name: "test", typ: typ, value: value, attrs: attrs})
+}
+
+func TestValidateConstraints(t *testing.T) {
+	testCases := []struct {
+		inputs         []*Input
+		expectedErrors []string
+	}{
+		// required
+		{[]*Input{newConstrainedTestInput(InputText, "", map[string]string{"required": ""})}, []string{"test is required."}},
+		{[]*Input{newConstrainedTestInput(InputText, "foo", map[string]string{"required": ""})}, nil},
+		{[]*Input{newConstrainedTestInput(InputText, "", map[string]string{"required": "", "disabled": ""})}, nil},
+		{[]*Input{newConstrainedTestInput(InputHidden, "", map[string]string{"required": ""})}, nil},
+		{[]*Input{NewInput(testElement{name: "test", typ: InputCheckbox, value: "on", attrs: map[string]string{"required": ""}})}, []string{"test is required."}},
+		{[]*Input{NewInput(testElement{name: "test", typ: InputCheckbox, value: "on", checked: true, attrs: map[string]string{"required": ""}})}, nil},
+		{[]*Input{
+			NewInput(testElement{name: "test", typ: InputRadio, value: "a", attrs: map[string]string{"required": ""}}),
+			NewInput(testElement{name: "test", typ: InputRadio, value: "b", attrs: map[string]string{"required": ""}}),
+		}, []string{"test is required."}},
+		{[]*Input{
+			NewInput(testElement{name: "test", typ: InputRadio, value: "a", attrs: map[string]string{"required": ""}}),
+			NewInput(testElement{name: "test", typ: InputRadio, value: "b", checked: true}),
+		}, nil},
+		// minlength, maxlength, and pattern
+		{[]*Input{newConstrainedTestInput(InputText, "ab", map[string]string{"minlength": "3"})}, []string{"test must be at least 3 characters long."}},
+		{[]*Input{newConstrainedTestInput(InputTextArea, "abcd", map[string]string{"maxlength": "3"})}, []string{"test must be at most 3 characters long."}},
+		{[]*Input{newConstrainedTestInput(InputText, "abcd", map[string]string{"maxlength": "foo"})}, nil},
+		{[]*Input{newConstrainedTestInput(InputText, "", map[string]string{"minlength": "3"})}, nil},
+		{[]*Input{newConstrainedTestInput(InputText, "abc", map[string]string{"pattern": "[a-z]+"})}, nil},
+		{[]*Input{newConstrainedTestInput(InputText, "abc1", map[string]string{"pattern": "[a-z]+"})}, []string{"test is not in the correct format."}},
+		{[]*Input{newConstrainedTestInput(InputText, "abc", map[string]string{"pattern": "a|abc"})}, nil},
+		{[]*Input{newConstrainedTestInput(InputText, "abc", map[string]string{"pattern": "[a-z"})}, nil},
+		// number min, max, and step
+		{[]*Input{newConstrainedTestInput(InputNumber, "5", map[string]string{"min": "1", "max": "10"})}, nil},
+		{[]*Input{newConstrainedTestInput(InputNumber, "0", map[string]string{"min": "1", "max": "10"})}, []string{"test must be greater than or equal to 1."}},
+		{[]*Input{newConstrainedTestInput(InputRange, "11", map[string]string{"min": "1", "max": "10"})}, []string{"test must be less than or equal to 10."}},
+		{[]*Input{newConstrainedTestInput(InputNumber, "foo", map[string]string{"min": "1", "max": "10"})}, []string{"test must be a number."}},
+		{[]*Input{newConstrainedTestInput(InputNumber, "0.3", map[string]string{"step": "0.1"})}, nil},
+		{[]*Input{newConstrainedTestInput(InputNumber, "0.35", map[string]string{"step": "0.1"})}, []string{"test must be a multiple of 0.1."}},
+		{[]*Input{newConstrainedTestInput(InputNumber, "7", map[string]string{"min": "1", "step": "6"})}, nil},
+		{[]*Input{newConstrainedTestInput(InputNumber, "6", map[string]string{"min": "1", "step": "6"})}, []string{"test must be 1 plus a multiple of 6."}},
+		{[]*Input{newConstrainedTestInput(InputNumber, "0.35", map[string]string{"step": "any"})}, nil},
+		// date and time min and max
+		{[]*Input{newConstrainedTestInput(InputDate, "2015-06-15", map[string]string{"min": "2015-06-01", "max": "2015-06-30"})}, nil},
+		{[]*Input{newConstrainedTestInput(InputDate, "2015-05-31", map[string]string{"min": "2015-06-01"})}, []string{"test must not be before 2015-06-01."}},
+		{[]*Input{newConstrainedTestInput(InputMonth, "2015-07", map[string]string{"max": "2015-06"})}, []string{"test must not be after 2015-06."}},
+		{[]*Input{newConstrainedTestInput(InputWeek, "2015-W01", map[string]string{"min": "2015-W02"})}, []string{"test must not be before 2015-W02."}},
+		{[]*Input{newConstrainedTestInput(InputDateTimeLocal, "2015-06-01T08:30", map[string]string{"min": "2015-06-01T09:00"})}, []string{"test must not be before 2015-06-01T09:00."}},
+		{[]*Input{newConstrainedTestInput(InputTime, "09:00", map[string]string{"min": "09:00", "max": "17:00"})}, nil},
+		{[]*Input{newConstrainedTestInput(InputTime, "23:00", map[string]string{"min": "22:00", "max": "06:00"})}, nil},
+		{[]*Input{newConstrainedTestInput(InputTime, "12:00", map[string]string{"min": "22:00", "max": "06:00"})}, []string{"test must be between 22:00 and 06:00."}},
+		{[]*Input{newConstrainedTestInput(InputDate, "foo", map[string]string{"min": "2015-06-01", "max": "2015-06-30"})}, []string{"test must be a valid time."}},
+		// Multiple constraints
+		{[]*Input{newConstrainedTestInput(InputText, "a1", map[string]string{"minlength": "3", "pattern": "[a-z]+"})}, []string{
+			"test must be at least 3 characters long.",
+			"test is not in the correct format.",
+		}},
+	}
+	for i, tc := range testCases {
+		form := NewForm(tc.inputs...)
+		form.ValidateConstraints()
+		var gotErrors []string
+		for _, err := range form.Errors {
+			gotErrors = append(gotErrors, err.Error())
+		}
+		if !reflect.DeepEqual(gotErrors, tc.expectedErrors) {
+			t.Errorf("Test case %d: expected errors %v but got %v", i, tc.expectedErrors, gotErrors)
+		}
+	}
+}
+
+func TestValidateConstraintsCodes(t *testing.T) {
+	testCases := []struct {
+		input          *Input
+		expectedCode   string
+		expectedParams Params
+	}{
+		{newConstrainedTestInput(InputText, "", map[string]string{"required": ""}), RuleRequired, nil},
+		{newConstrainedTestInput(InputText, "a1", map[string]string{"pattern": "[a-z]+"}), RulePattern, Params{"pattern": "[a-z]+"}},
+		{newConstrainedTestInput(InputNumber, "0", map[string]string{"min": "1"}), RuleMin, Params{"limit": "1"}},
+		{newConstrainedTestInput(InputDate, "2015-07-01", map[string]string{"max": "2015-06-30"}), RuleMax, Params{"limit": "2015-06-30"}},
+		{newConstrainedTestInput(InputNumber, "0.35", map[string]string{"step": "0.1"}), RuleStep, Params{"step": 0.1, "base": 0.0}},
+	}
+	for i, tc := range testCases {
+		form := NewForm(tc.input)
+		form.ValidateConstraints()
+		if len(form.Errors) != 1 {
+			t.Errorf("Test case %d: expected exactly one error but got %v", i, form.Errors)
+			continue
+		}
+		valErr := form.Errors[0].(*ValidationError)
+		if valErr.Code != tc.expectedCode || !reflect.DeepEqual(valErr.Params, tc.expectedParams) {
+			t.Errorf("Test case %d: expected code %q and params %v but got %q and %v", i, tc.expectedCode, tc.expectedParams, valErr.Code, valErr.Params)
+		}
+	}
+}
+
+func TestValidityRules(t *testing.T) {
+	testCases := []struct {
+		input         *Input
+		state         validityState
+		expectedRules []rule
+	}{
+		{newConstrainedTestInput(InputText, "", nil), validityState{}, []rule{}},
+		{newConstrainedTestInput(InputText, "", nil), validityState{valueMissing: true}, []rule{{code: RuleRequired}}},
+		{newConstrainedTestInput(InputNumber, "", nil), validityState{badInput: true}, []rule{{code: RuleNumber}}},
+		{newConstrainedTestInput(InputDate, "", nil), validityState{badInput: true}, []rule{{code: RuleTime}}},
+		{newConstrainedTestInput(InputEmail, "foo", nil), validityState{typeMismatch: true}, []rule{{code: RuleEmail}}},
+		{newConstrainedTestInput(InputURL, "foo", nil), validityState{typeMismatch: true}, []rule{{code: RuleURL}}},
+		{newConstrainedTestInput(InputText, "ab", map[string]string{"minlength": "3"}), validityState{tooShort: true}, []rule{{RuleMinLength, Params{"limit": 3}}}},
+		{newConstrainedTestInput(InputText, "abcd", map[string]string{"maxlength": "3"}), validityState{tooLong: true}, []rule{{RuleMaxLength, Params{"limit": 3}}}},
+		{newConstrainedTestInput(InputText, "a1", map[string]string{"pattern": "[a-z]+"}), validityState{patternMismatch: true}, []rule{{RulePattern, Params{"pattern": "[a-z]+"}}}},
+		{newConstrainedTestInput(InputNumber, "0", map[string]string{"min": "1"}), validityState{rangeUnderflow: true}, []rule{{RuleMin, Params{"limit": "1"}}}},
+		{newConstrainedTestInput(InputNumber, "5", map[string]string{"max": "4"}), validityState{rangeOverflow: true}, []rule{{RuleMax, Params{"limit": "4"}}}},
+		{newConstrainedTestInput(InputTime, "12:00", map[string]string{"min": "22:00", "max": "06:00"}), validityState{rangeUnderflow: true, rangeOverflow: true}, []rule{{RuleRange, Params{"min": "22:00", "max": "06:00"}}}},
+		{newConstrainedTestInput(InputNumber, "0.35", map[string]string{"step": "0.1"}), validityState{stepMismatch: true}, []rule{{RuleStep, Params{"step": 0.1, "base": 0.0}}}},
+		{newConstrainedTestInput(InputEmail, "", map[string]string{"minlength": "3"}), validityState{valueMissing: true, typeMismatch: true}, []rule{{code: RuleRequired}, {code: RuleEmail}}},
+	}
+	for i, tc := range testCases {
+		got := validityRules(tc.input, tc.state)
+		if !reflect.DeepEqual(got, tc.expectedRules) {
+			t.Errorf("Test case %d: expected rules %v but got %v", i, tc.expectedRules, got)
+		}
+	}
+}
+
+func TestValidityMessage(t *testing.T) {
+	// The messages should match those which ValidateConstraints adds for the
+	// same constraints.
+	testCases := []struct {
+		input *Input
+		state validityState
+	}{
+		{newConstrainedTestInput(InputText, "", map[string]string{"required": ""}), validityState{valueMissing: true}},
+		{newConstrainedTestInput(InputText, "ab", map[string]string{"minlength": "3"}), validityState{tooShort: true}},
+		{newConstrainedTestInput(InputText, "abcd", map[string]string{"maxlength": "3"}), validityState{tooLong: true}},
+		{newConstrainedTestInput(InputText, "a1", map[string]string{"pattern": "[a-z]+"}), validityState{patternMismatch: true}},
+		{newConstrainedTestInput(InputNumber, "0", map[string]string{"min": "1"}), validityState{rangeUnderflow: true}},
+		{newConstrainedTestInput(InputNumber, "5", map[string]string{"max": "4"}), validityState{rangeOverflow: true}},
+		{newConstrainedTestInput(InputNumber, "0.35", map[string]string{"step": "0.1"}), validityState{stepMismatch: true}},
+		{newConstrainedTestInput(InputNumber, "3", map[string]string{"min": "1", "step": "5"}), validityState{stepMismatch: true}},
+		{newConstrainedTestInput(InputDate, "2015-01-01", map[string]string{"min": "2015-06-01"}), validityState{rangeUnderflow: true}},
+		{newConstrainedTestInput(InputDate, "2015-12-01", map[string]string{"max": "2015-06-01"}), validityState{rangeOverflow: true}},
+		{newConstrainedTestInput(InputTime, "12:00", map[string]string{"min": "22:00", "max": "06:00"}), validityState{rangeUnderflow: true, rangeOverflow: true}},
+	}
+	for i, tc := range testCases {
+		form := NewForm(tc.input)
+		form.ValidateConstraints()
+		if len(form.Errors) != 1 {
+			t.Errorf("Test case %d: expected exactly one error from ValidateConstraints but got %v", i, form.Errors)
+			continue
+		}
+		expected := form.Errors[0].Error()
+		rules := validityRules(tc.input, tc.state)
+		if got := form.Validate("test").validityMessage(rules[0]); got != expected {
+			t.Errorf("Test case %d: expected message %q but got %q", i, expected, got)
+		}
+	}
+}
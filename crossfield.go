@@ -0,0 +1,235 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"strings"
+	"time"
+)
+
+// isTimeInput returns true iff Input.Time knows how to parse the value of an
+// input with the given type as something other than an rfc3339 datetime.
+func isTimeInput(inputType InputType) bool {
+	switch inputType {
+	case InputDate, InputDateTime, InputDateTimeLocal, InputMonth, InputWeek, InputTime:
+		return true
+	}
+	return false
+}
+
+// isFilled returns true iff the browser would submit at least one non-empty
+// value for the inputs identified by inputName.
+func (form *Form) isFilled(inputName string) bool {
+	values, _ := form.GetStrings(inputName)
+	for _, value := range values {
+		if value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// EqualTo adds a validation error to the form if the value of the input is not
+// equal to the value of the input identified by otherName, e.g. to check that a
+// password was confirmed correctly. Unlike most validations, EqualTo does not
+// skip empty inputs. An input which does not exist is treated as if its value
+// were empty.
+func (val *InputValidation) EqualTo(otherName string) *InputValidation {
+	defer val.localize()()
+	return val.EqualTof(otherName, defaultFormats[RuleEqualTo], val.label(), val.Form.Label(otherName))
+}
+
+// EqualTof is like EqualTo but allows you to specify a custom error message.
+// The arguments format and args work exactly like they do in fmt.Sprintf.
+func (val *InputValidation) EqualTof(otherName string, format string, args ...interface{}) *InputValidation {
+	value, otherValue := "", ""
+	if val.Input != nil {
+		value = val.Input.value()
+	}
+	if other := val.Form.Inputs[otherName]; other != nil {
+		otherValue = other.value()
+	}
+	if value != otherValue {
+		val.addError(rule{RuleEqualTo, Params{"other": otherName}}, format, args...)
+	}
+	return val
+}
+
+// comparesAsTimes returns true iff the input and the input identified by
+// otherName are compared as times (see Input.Time). That is the case if the
+// input has one of the date or time types, or if it has the type InputDefault
+// (e.g. because it was created by ParseValues) and the values of both inputs
+// can be converted to times.
+func (val *InputValidation) comparesAsTimes(otherName string) bool {
+	if isTimeInput(val.inputType()) {
+		return true
+	}
+	other := val.Form.Inputs[otherName]
+	if val.inputType() != InputDefault || val.isEmpty() || other == nil {
+		return false
+	}
+	if _, err := val.Input.Time(); err != nil {
+		return false
+	}
+	_, err := other.Time()
+	return err == nil
+}
+
+// compareToField compares the value of the input to the value of the input
+// identified by otherName and adds a validation error for the rule r if ok
+// returns false for the result of the comparison (-1, 0, or 1). The values are
+// compared as times if comparesAsTimes returns true, and as numbers otherwise.
+// If either input does not exist or is empty, compareToField does nothing. If
+// the value of the other input is not valid, compareToField also does nothing,
+// unless the input has the type InputDefault. Since the type of such an input
+// is not known, it is not validated on its own, so compareToField adds an error
+// for r instead.
+func (val *InputValidation) compareToField(r rule, otherName string, ok func(cmp int) bool, format string, args ...interface{}) *InputValidation {
+	other := val.Form.Inputs[otherName]
+	if other == nil || other.value() == "" {
+		return val
+	}
+	if val.comparesAsTimes(otherName) {
+		otherTime, err := other.Time()
+		if err != nil {
+			return val
+		}
+		return val.validateTime(r, func(value time.Time) bool {
+			switch {
+			case value.Before(otherTime):
+				return ok(-1)
+			case value.After(otherTime):
+				return ok(1)
+			}
+			return ok(0)
+		}, format, args...)
+	}
+	otherFloat, err := other.Float()
+	if err != nil {
+		if val.inputType() == InputDefault && !val.isEmpty() {
+			val.addError(r, format, args...)
+		}
+		return val
+	}
+	return val.validateFloat(r, func(value float64) bool {
+		switch {
+		case value < otherFloat:
+			return ok(-1)
+		case value > otherFloat:
+			return ok(1)
+		}
+		return ok(0)
+	}, format, args...)
+}
+
+// GreaterThanField adds a validation error to the form if the value of the
+// input is not greater than the value of the input identified by otherName.
+// Inputs with one of the date or time types are compared as times (see
+// Input.Time), so GreaterThanField can be used to check that an end date is
+// after a start date. So are inputs with the type InputDefault, such as the
+// inputs created by ParseValues, if both values can be converted to times. All
+// other inputs are compared as numbers. If either input does not exist or is
+// empty, GreaterThanField does nothing. If the value of the other input is not
+// valid, it also does nothing, unless the input has the type InputDefault, in
+// which case it adds an error.
+func (val *InputValidation) GreaterThanField(otherName string) *InputValidation {
+	defer val.localize()()
+	if val.comparesAsTimes(otherName) {
+		return val.GreaterThanFieldf(otherName, defaultFormats[RuleGreaterThan+timeVariant], val.label(), val.Form.Label(otherName))
+	}
+	return val.GreaterThanFieldf(otherName, defaultFormats[RuleGreaterThan], val.label(), val.Form.Label(otherName))
+}
+
+// GreaterThanFieldf is like GreaterThanField but allows you to specify a custom
+// error message. The arguments format and args work exactly like they do in
+// fmt.Sprintf.
+func (val *InputValidation) GreaterThanFieldf(otherName string, format string, args ...interface{}) *InputValidation {
+	return val.compareToField(rule{RuleGreaterThan, Params{"other": otherName}}, otherName, func(cmp int) bool {
+		return cmp > 0
+	}, format, args...)
+}
+
+// LessThanField adds a validation error to the form if the value of the input
+// is not less than the value of the input identified by otherName. It compares
+// the inputs in the same way as GreaterThanField.
+func (val *InputValidation) LessThanField(otherName string) *InputValidation {
+	defer val.localize()()
+	if val.comparesAsTimes(otherName) {
+		return val.LessThanFieldf(otherName, defaultFormats[RuleLessThan+timeVariant], val.label(), val.Form.Label(otherName))
+	}
+	return val.LessThanFieldf(otherName, defaultFormats[RuleLessThan], val.label(), val.Form.Label(otherName))
+}
+
+// LessThanFieldf is like LessThanField but allows you to specify a custom error
+// message. The arguments format and args work exactly like they do in
+// fmt.Sprintf.
+func (val *InputValidation) LessThanFieldf(otherName string, format string, args ...interface{}) *InputValidation {
+	return val.compareToField(rule{RuleLessThan, Params{"other": otherName}}, otherName, func(cmp int) bool {
+		return cmp < 0
+	}, format, args...)
+}
+
+// RequiredIf adds a validation error to the form if the input is not included
+// in the form or is empty, but only if the input identified by otherName has
+// the given value. For checkboxes, radio buttons, and select elements, any of
+// the values that the browser would submit may match (see Form.GetStrings).
+func (val *InputValidation) RequiredIf(otherName string, value string) *InputValidation {
+	defer val.localize()()
+	return val.RequiredIfF(otherName, value, defaultFormats[RuleRequiredIf], val.label(), val.Form.Label(otherName), value)
+}
+
+// RequiredIfF is like RequiredIf but allows you to specify a custom error
+// message. The arguments format and args work exactly like they do in
+// fmt.Sprintf.
+func (val *InputValidation) RequiredIfF(otherName string, value string, format string, args ...interface{}) *InputValidation {
+	if values, _ := val.Form.GetStrings(otherName); containsString(values, value) {
+		return val.required(rule{RuleRequiredIf, Params{"other": otherName, "value": value}}, format, args...)
+	}
+	return val
+}
+
+// RequiredUnless adds a validation error to the form if the input is not
+// included in the form or is empty, unless the input identified by otherName
+// has the given value. The value of the other input is checked in the same way
+// as for RequiredIf.
+func (val *InputValidation) RequiredUnless(otherName string, value string) *InputValidation {
+	defer val.localize()()
+	return val.RequiredUnlessf(otherName, value, defaultFormats[RuleRequiredUnless], val.label(), val.Form.Label(otherName), value)
+}
+
+// RequiredUnlessf is like RequiredUnless but allows you to specify a custom
+// error message. The arguments format and args work exactly like they do in
+// fmt.Sprintf.
+func (val *InputValidation) RequiredUnlessf(otherName string, value string, format string, args ...interface{}) *InputValidation {
+	if values, _ := val.Form.GetStrings(otherName); !containsString(values, value) {
+		return val.required(rule{RuleRequiredUnless, Params{"other": otherName, "value": value}}, format, args...)
+	}
+	return val
+}
+
+// RequiredWith adds a validation error to the form if the input is not
+// included in the form or is empty, but only if at least one of the inputs
+// identified by otherNames has a non-empty value.
+func (val *InputValidation) RequiredWith(otherNames ...string) *InputValidation {
+	defer val.localize()()
+	labels := make([]string, len(otherNames))
+	for i, otherName := range otherNames {
+		labels[i] = val.Form.Label(otherName)
+	}
+	return val.RequiredWithf(otherNames, defaultFormats[RuleRequiredWith], val.label(), strings.Join(labels, " or "))
+}
+
+// RequiredWithf is like RequiredWith but allows you to specify a custom error
+// message. The arguments format and args work exactly like they do in
+// fmt.Sprintf.
+func (val *InputValidation) RequiredWithf(otherNames []string, format string, args ...interface{}) *InputValidation {
+	for _, otherName := range otherNames {
+		if val.Form.isFilled(otherName) {
+			return val.required(rule{RuleRequiredWith, Params{"others": otherNames}}, format, args...)
+		}
+	}
+	return val
+}
@@ -0,0 +1,211 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestCrossFieldValidations(t *testing.T) {
+	testCases := []struct {
+		inputs         []*Input
+		validate       func(form *Form)
+		expectedErrors []string
+	}{
+		// EqualTo
+		{
+			[]*Input{newTestInput("password", InputPassword, "secret"), newTestInput("confirm", InputPassword, "secret")},
+			func(form *Form) { form.Validate("confirm").EqualTo("password") },
+			nil,
+		},
+		{
+			[]*Input{newTestInput("password", InputPassword, "secret"), newTestInput("confirm", InputPassword, "Secret")},
+			func(form *Form) { form.Validate("confirm").EqualTo("password") },
+			[]string{"confirm must be equal to password."},
+		},
+		{
+			[]*Input{newTestInput("password", InputPassword, "secret"), newTestInput("confirm", InputPassword, "")},
+			func(form *Form) { form.Validate("confirm").EqualTof("password", "Passwords do not match.") },
+			[]string{"Passwords do not match."},
+		},
+		{
+			[]*Input{newTestInput("password", InputPassword, "secret")},
+			func(form *Form) { form.Validate("confirm").EqualTo("password") },
+			[]string{"confirm must be equal to password."},
+		},
+		// GreaterThanField and LessThanField
+		{
+			[]*Input{newTestInput("start", InputDate, "2015-06-01"), newTestInput("end", InputDate, "2015-06-02")},
+			func(form *Form) { form.Validate("end").GreaterThanField("start") },
+			nil,
+		},
+		{
+			[]*Input{newTestInput("start", InputDate, "2015-06-01"), newTestInput("end", InputDate, "2015-06-01")},
+			func(form *Form) { form.Validate("end").GreaterThanField("start") },
+			[]string{"end must be after start."},
+		},
+		{
+			[]*Input{newTestInput("start", InputDate, "2015-06-01"), newTestInput("end", InputDate, "2015-05-01")},
+			func(form *Form) { form.Validate("start").LessThanField("end") },
+			[]string{"start must be before end."},
+		},
+		{
+			[]*Input{newTestInput("min", InputNumber, "9"), newTestInput("max", InputNumber, "10")},
+			func(form *Form) { form.Validate("max").GreaterThanField("min") },
+			nil,
+		},
+		{
+			[]*Input{newTestInput("min", InputNumber, "10"), newTestInput("max", InputNumber, "9.5")},
+			func(form *Form) { form.Validate("max").GreaterThanField("min") },
+			[]string{"max must be greater than min."},
+		},
+		{
+			[]*Input{newTestInput("min", InputNumber, "10"), newTestInput("max", InputNumber, "9.5")},
+			func(form *Form) { form.Validate("min").LessThanFieldf("max", "custom") },
+			[]string{"custom"},
+		},
+		{
+			[]*Input{newTestInput("min", InputNumber, "10"), newTestInput("max", InputNumber, "foo")},
+			func(form *Form) { form.Validate("max").GreaterThanField("min") },
+			[]string{"max must be a number."},
+		},
+		{
+			[]*Input{newTestInput("min", InputNumber, "foo"), newTestInput("max", InputNumber, "1")},
+			func(form *Form) { form.Validate("max").GreaterThanField("min") },
+			nil,
+		},
+		{
+			[]*Input{newTestInput("max", InputNumber, "1")},
+			func(form *Form) { form.Validate("max").GreaterThanField("min") },
+			nil,
+		},
+		// RequiredIf and RequiredUnless
+		{
+			[]*Input{NewInput(testElement{name: "country", typ: InputSelect, value: "US", selectedValues: []string{"US"}}), newTestInput("state", InputText, "")},
+			func(form *Form) { form.Validate("state").RequiredIf("country", "US") },
+			[]string{"state is required when country is US."},
+		},
+		{
+			[]*Input{NewInput(testElement{name: "country", typ: InputSelect, value: "CA", selectedValues: []string{"CA"}}), newTestInput("state", InputText, "")},
+			func(form *Form) { form.Validate("state").RequiredIf("country", "US") },
+			nil,
+		},
+		{
+			[]*Input{
+				newCheckedTestInput("country", InputRadio, "US", true),
+				newCheckedTestInput("country", InputRadio, "CA", false),
+			},
+			func(form *Form) { form.Validate("state").RequiredIfF("country", "US", "custom") },
+			[]string{"custom"},
+		},
+		{
+			[]*Input{newTestInput("country", InputText, "CA")},
+			func(form *Form) { form.Validate("zip").RequiredUnless("country", "US") },
+			[]string{"zip is required unless country is US."},
+		},
+		{
+			[]*Input{newTestInput("country", InputText, "US")},
+			func(form *Form) { form.Validate("zip").RequiredUnless("country", "US") },
+			nil,
+		},
+		{
+			nil,
+			func(form *Form) { form.Validate("zip").RequiredUnlessf("country", "US", "custom") },
+			[]string{"custom"},
+		},
+		// RequiredWith
+		{
+			[]*Input{newTestInput("phone", InputTel, ""), newTestInput("email", InputEmail, "foo@example.com")},
+			func(form *Form) { form.Validate("name").RequiredWith("phone", "email") },
+			[]string{"name is required when phone or email is present."},
+		},
+		{
+			[]*Input{newTestInput("phone", InputTel, ""), newTestInput("email", InputEmail, "")},
+			func(form *Form) { form.Validate("name").RequiredWith("phone", "email") },
+			nil,
+		},
+		{
+			[]*Input{newCheckedTestInput("newsletter", InputCheckbox, "on", false)},
+			func(form *Form) { form.Validate("email").RequiredWith("newsletter") },
+			nil,
+		},
+		{
+			[]*Input{newCheckedTestInput("newsletter", InputCheckbox, "on", true)},
+			func(form *Form) { form.Validate("email").RequiredWithf([]string{"newsletter"}, "custom") },
+			[]string{"custom"},
+		},
+	}
+	for i, tc := range testCases {
+		form := NewForm(tc.inputs...)
+		tc.validate(form)
+		var gotErrors []string
+		for _, err := range form.Errors {
+			gotErrors = append(gotErrors, err.Error())
+		}
+		if !reflect.DeepEqual(gotErrors, tc.expectedErrors) {
+			t.Errorf("Test case %d: expected errors %q but got %q", i, tc.expectedErrors, gotErrors)
+		}
+	}
+}
+
+func TestCrossFieldValidationsParseValues(t *testing.T) {
+	// Inputs created by ParseValues have the type InputDefault, but should be
+	// compared in the same way as in the browser.
+	testCases := []struct {
+		values         url.Values
+		validate       func(form *Form)
+		expectedErrors []string
+	}{
+		{
+			url.Values{"start": {"2024-05-10"}, "end": {"2024-01-01"}},
+			func(form *Form) { form.Validate("end").GreaterThanField("start") },
+			[]string{"end must be after start."},
+		},
+		{
+			url.Values{"start": {"2024-01-01"}, "end": {"2024-05-10"}},
+			func(form *Form) { form.Validate("end").GreaterThanField("start") },
+			nil,
+		},
+		{
+			url.Values{"start": {"2024-05-10T08:00"}, "end": {"2024-05-10T09:30"}},
+			func(form *Form) { form.Validate("end").LessThanField("start") },
+			[]string{"end must be before start."},
+		},
+		{
+			url.Values{"min": {"10"}, "max": {"9"}},
+			func(form *Form) { form.Validate("max").GreaterThanField("min") },
+			[]string{"max must be greater than min."},
+		},
+		{
+			url.Values{"min": {"9"}, "max": {"10"}},
+			func(form *Form) { form.Validate("max").GreaterThanField("min") },
+			nil,
+		},
+		{
+			url.Values{"start": {"soon"}, "end": {"2024-05-10"}},
+			func(form *Form) { form.Validate("end").GreaterThanField("start") },
+			[]string{"end must be greater than start."},
+		},
+		{
+			url.Values{"start": {"2024-05-10"}},
+			func(form *Form) { form.Validate("end").GreaterThanField("start") },
+			nil,
+		},
+	}
+	for i, tc := range testCases {
+		form := ParseValues(tc.values)
+		tc.validate(form)
+		var gotErrors []string
+		for _, err := range form.Errors {
+			gotErrors = append(gotErrors, err.Error())
+		}
+		if !reflect.DeepEqual(gotErrors, tc.expectedErrors) {
+			t.Errorf("Test case %d: expected errors %q but got %q", i, tc.expectedErrors, gotErrors)
+		}
+	}
+}
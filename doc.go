@@ -4,6 +4,11 @@
 // great as a stand-alone package or in combination with other Humble packages
 // (https://github.com/go-humble).
 //
+// The same validations and bindings can also run on the server. Use
+// ParseValues or ParseRequest to create a Form from submitted values instead of
+// an html form element. Only the code which touches the DOM (e.g. Parse) is
+// restricted to the js build tag.
+//
 // Version 0.0.2
 //
 // For the full source code, a quickstart guide, and more information visit
@@ -0,0 +1,149 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+//go:build js
+// +build js
+
+package form
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+	"honnef.co/go/js/dom"
+)
+
+// Parse creates a and returns a Form object from the given form element. Every
+// input, select, textarea, and button element in the form is converted to an
+// Input. All other elements (e.g. fieldsets) are ignored.
+func Parse(formElement dom.Element) (*Form, error) {
+	htmlFormElement, ok := formElement.(*dom.HTMLFormElement)
+	if !ok {
+		return nil, fmt.Errorf("form: Argument to Parse must be a *dom.HTMLFormElement. (Got %T)", formElement)
+	}
+	form := NewForm()
+	for _, el := range htmlFormElement.Elements() {
+		domElement, err := NewDOMElement(el)
+		if err != nil {
+			// Skip elements which are not inputs, such as fieldsets.
+			continue
+		}
+		form.addInput(NewInput(domElement))
+	}
+	return form, nil
+}
+
+// DOMElement is an Element which is backed by an html element in the DOM. The
+// underlying html element is always one of *dom.HTMLInputElement,
+// *dom.HTMLSelectElement, *dom.HTMLTextAreaElement, or *dom.HTMLButtonElement.
+// Because DOMElement embeds dom.HTMLElement, all the methods of the underlying
+// element are available directly.
+type DOMElement struct {
+	dom.HTMLElement
+}
+
+// NewDOMElement wraps the given html element in a DOMElement. It returns an
+// error if el is not an *dom.HTMLInputElement, *dom.HTMLSelectElement,
+// *dom.HTMLTextAreaElement, or *dom.HTMLButtonElement.
+func NewDOMElement(el dom.HTMLElement) (*DOMElement, error) {
+	switch el.(type) {
+	case *dom.HTMLInputElement, *dom.HTMLSelectElement, *dom.HTMLTextAreaElement, *dom.HTMLButtonElement:
+		return &DOMElement{HTMLElement: el}, nil
+	}
+	return nil, fmt.Errorf("form: Don't know how to create an input from element of type %T", el)
+}
+
+// Name satisfies the Name method of Element.
+func (el *DOMElement) Name() string {
+	return el.Underlying().Get("name").String()
+}
+
+// Type satisfies the Type method of Element. For input and button elements, it
+// attempts to determine the type by first getting the type attribute directly.
+// This is more reliable as some browsers will always return "text" as the type
+// if the type attribute is not recognized/supported. If the type attribute is
+// missing, it falls back to using what the browser thinks the type is (which is
+// probably "text" for inputs and "submit" for buttons). For select and textarea
+// elements, it always uses the type reported by the browser.
+func (el *DOMElement) Type() InputType {
+	switch el.HTMLElement.(type) {
+	case *dom.HTMLInputElement, *dom.HTMLButtonElement:
+		if inputType := InputType(el.GetAttribute("type")); inputType != "" {
+			return inputType
+		}
+	}
+	return InputType(el.Underlying().Get("type").String())
+}
+
+// Value satisfies the Value method of Element.
+func (el *DOMElement) Value() string {
+	return el.Underlying().Get("value").String()
+}
+
+// SelectedValues satisfies the SelectedValues method of Element.
+func (el *DOMElement) SelectedValues() []string {
+	selectEl, ok := el.HTMLElement.(*dom.HTMLSelectElement)
+	if !ok {
+		return nil
+	}
+	selectedValues := []string{}
+	for _, option := range selectEl.SelectedOptions() {
+		selectedValues = append(selectedValues, option.Value)
+	}
+	return selectedValues
+}
+
+// Checked satisfies the Checked method of Element.
+func (el *DOMElement) Checked() bool {
+	inputEl, ok := el.HTMLElement.(*dom.HTMLInputElement)
+	if !ok {
+		return false
+	}
+	return inputEl.Checked
+}
+
+// SetValue satisfies the SetValue method of WritableElement.
+func (el *DOMElement) SetValue(value string) {
+	el.Underlying().Set("value", value)
+}
+
+// SetChecked satisfies the SetChecked method of WritableElement.
+func (el *DOMElement) SetChecked(checked bool) {
+	if inputEl, ok := el.HTMLElement.(*dom.HTMLInputElement); ok {
+		inputEl.Checked = checked
+	}
+}
+
+// SetSelectedValues satisfies the SetSelectedValues method of WritableElement.
+func (el *DOMElement) SetSelectedValues(values []string) {
+	selectEl, ok := el.HTMLElement.(*dom.HTMLSelectElement)
+	if !ok {
+		return
+	}
+	for _, option := range selectEl.Options() {
+		option.Selected = containsString(values, option.Value)
+	}
+}
+
+// Label returns the text of the first label element associated with the
+// element, either because the label has a for attribute which matches the id of
+// the element or because the label contains the element. The text of any form
+// controls inside the label (e.g. the options of a select element) is ignored,
+// whitespace is collapsed, and a trailing colon is removed. Label returns an
+// empty string if there is no label or the browser does not support the labels
+// property. It is used by Form.Label.
+func (el *DOMElement) Label() string {
+	labels := el.Underlying().Get("labels")
+	if labels == js.Undefined || labels == nil || labels.Length() == 0 {
+		return ""
+	}
+	label := dom.WrapElement(labels.Index(0)).CloneNode(true).(dom.Element)
+	for _, control := range label.QuerySelectorAll("input, select, textarea, button") {
+		control.ParentNode().RemoveChild(control)
+	}
+	text := strings.Join(strings.Fields(label.TextContent()), " ")
+	return strings.TrimSpace(strings.TrimSuffix(text, ":"))
+}
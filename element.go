@@ -0,0 +1,46 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+// Element is the source of an Input. It provides access to the name, type,
+// value(s), checked state, and attributes of an html form control without
+// depending on any particular environment. In the browser, Parse uses a
+// *DOMElement. On the server, ParseValues and ParseRequest use an Element which
+// is backed by submitted form values. You can also implement Element yourself
+// and pass it to NewInput.
+type Element interface {
+	// Name returns the value of the name attribute.
+	Name() string
+	// Type returns the type of the element. See the Type field of Input.
+	Type() InputType
+	// Value returns the current value of the element.
+	Value() string
+	// SelectedValues returns the values of all the selected options for select
+	// elements, and nil for all other elements.
+	SelectedValues() []string
+	// Checked returns true iff the element is a checkbox or radio and it is
+	// checked.
+	Checked() bool
+	// GetAttribute returns the value of the attribute with the given name, or
+	// an empty string if the element does not have the attribute.
+	GetAttribute(name string) string
+	// HasAttribute returns true iff the element has an attribute with the given
+	// name.
+	HasAttribute(name string) bool
+}
+
+// WritableElement is an Element which can be updated. Form.Fill and
+// Input.UpdateElement use it to write values back to the original element.
+type WritableElement interface {
+	Element
+	// SetValue sets the value of the element.
+	SetValue(value string)
+	// SetChecked sets the checked state of a checkbox or radio.
+	SetChecked(checked bool)
+	// SetSelectedValues selects exactly the options of a select element whose
+	// values are in values.
+	SetSelectedValues(values []string)
+}
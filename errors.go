@@ -0,0 +1,120 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	// errRequiredMissing is the underlying error of a BindError when a field
+	// has the required option but there is no matching input.
+	errRequiredMissing = errors.New("required input was not found")
+	// errRequiredEmpty is the underlying error of a BindError when a field has
+	// the required option but the matching input is empty.
+	errRequiredEmpty = errors.New("required input was empty")
+)
+
+// BindError describes a failure to bind a single input to a struct field.
+type BindError struct {
+	// Field is the qualified name of the struct field, e.g. "Billing.Street" or
+	// "Items[0].Quantity".
+	Field string
+	// InputName is the name of the input. If there is no input for the field,
+	// it is the name the input would usually have, e.g. "name" for a field
+	// named Name without a form tag.
+	InputName string
+	// InputType is the type of the input. It is empty if the input was not
+	// found.
+	InputType InputType
+	// RawValue is the value of the input which could not be bound.
+	RawValue string
+	// Err is the underlying error, e.g. the error returned from strconv.Atoi.
+	Err error
+	// fieldType is the type of the struct field. It is used to choose a
+	// message in Form.AddBindErrors.
+	fieldType reflect.Type
+}
+
+// Error satisfies the Error method of the builtin error interface.
+func (e *BindError) Error() string {
+	return fmt.Sprintf("form: Could not bind input %s with value %q to struct field %s: %s",
+		e.InputName, e.RawValue, e.Field, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+// rule returns the rule code (see ValidationError.Code) and a human-readable
+// message for the error which is suitable for showing to users, using label to
+// refer to the input. The code and message match those of the corresponding validation,
+// e.g. RuleInteger and "age must be an integer." for an int field.
+func (e *BindError) rule(label string) (string, string) {
+	code := e.code()
+	return code, fmt.Sprintf(defaultFormats[code], label)
+}
+
+// code returns the rule code for the error. See rule.
+func (e *BindError) code() string {
+	if e.Err == errRequiredMissing || e.Err == errRequiredEmpty {
+		return RuleRequired
+	}
+	if e.fieldType == nil {
+		return RuleInvalid
+	}
+	underlyingType := getUnderlyingFieldType(e.fieldType)
+	switch underlyingType {
+	case timeType, clockType:
+		return RuleTime
+	case weekType:
+		return RuleWeek
+	}
+	switch underlyingType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return RuleInteger
+	case reflect.Float32, reflect.Float64:
+		return RuleNumber
+	case reflect.Bool:
+		return RuleBool
+	}
+	return RuleInvalid
+}
+
+// BindErrors is returned by Bind when one or more inputs could not be bound. It
+// holds a BindError for each failure, in the order the fields were bound.
+type BindErrors []*BindError
+
+// Error satisfies the Error method of the builtin error interface.
+func (errs BindErrors) Error() string {
+	if len(errs) == 1 {
+		return errs[0].Error()
+	}
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("form: %d errors occurred while binding:\n\t%s", len(errs), strings.Join(msgs, "\n\t"))
+}
+
+// AddBindErrors converts each error in errs to a ValidationError and adds it
+// to form.Errors, so that errors from Bind can be shown alongside errors from
+// validations. The messages match the messages of the corresponding
+// validations, e.g. "age must be an integer." for an int field, and so do the
+// rule codes, e.g. RuleInteger. Like the messages of the validations, they use
+// the label of the input and can be replaced by templates from form.Catalog.
+func (form *Form) AddBindErrors(errs BindErrors) {
+	for _, err := range errs {
+		val := form.Validate(err.InputName)
+		code, msg := err.rule(val.label())
+		val.addDefaultError(rule{code: code}, "%s", msg)
+	}
+}
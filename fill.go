@@ -0,0 +1,386 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Filler has a single method, FillForm, which fills the form inputs with
+// values from the receiver. It can be used to override the behavior of Fill.
+type Filler interface {
+	FillForm(*Form) error
+}
+
+// InputFiller has a single method, FillInput, which sets the value of an
+// input from the receiver. It can be used to override the behavior of Fill.
+// Typically, InputFiller should be implemented by some type which is a field of
+// the struct passed to Fill, and which also implements InputBinder. FillInput
+// should change the RawValue, Checked, or SelectedValues of the input. Fill
+// will write the changes to the underlying element afterwards.
+type InputFiller interface {
+	FillInput(*Input) error
+}
+
+// inputFillerType is the reflect.Type of the InputFiller interface.
+var inputFillerType = reflect.TypeOf([]InputFiller{}).Elem()
+
+// Fill is the inverse of Bind. It sets the values of the form inputs from the
+// fields of v, which must be a struct or a pointer to a struct, and writes them
+// to the underlying elements (see WritableElement). This is useful for
+// rendering a form which edits an existing model. Fill performs a one-way,
+// one-time update.
+//
+// Fill matches struct fields to inputs using exactly the same rules as Bind,
+// including form struct tags, nested structs, and collections. Strings and
+// numbers are formatted in the standard way, and time.Time values are formatted
+// according to the type of the input (e.g. "2006-01-02" for date inputs). The
+// zero time is formatted as an empty string. Clock and Week values are formatted
+// like the values of time and week inputs. Bool fields set the checked state
+// of checkboxes and radio buttons. For radio groups and checkbox groups, the
+// inputs whose values match the field value (or any of the elements of a slice
+// field) are checked and all others are unchecked. Similarly, select elements
+// have the options whose values match selected. Nil pointers leave the
+// corresponding inputs untouched, and so do pointers which lead back to a
+// struct which is already being filled, e.g. in a self-referencing model.
+//
+// If v implements Filler, Fill will just call v.FillForm. Similarly if any of
+// the fields of v implement InputFiller, Fill will call FillInput on the
+// specific field, even if the field is a struct or a slice. Fill returns an error if v is not a struct or a pointer to a
+// struct, or if any field type is not supported.
+func (form *Form) Fill(v interface{}) error {
+	// If v implements Filler, call v.FillForm.
+	if filler, ok := v.(Filler); ok {
+		return filler.FillForm(form)
+	}
+	val := reflect.ValueOf(v)
+	if val.Kind() == reflect.Ptr && val.IsNil() {
+		return fmt.Errorf("form: Argument to Fill was nil")
+	}
+	if reflect.Indirect(val).Kind() != reflect.Struct {
+		return fmt.Errorf("form: Fill expects a struct or a pointer to a struct, but got: %T", v)
+	}
+	err := form.fillNested(val, nil, map[interface{}]bool{})
+	// Checking or unchecking inputs may have changed which input belongs in
+	// form.Inputs.
+	for name, group := range form.Groups {
+		form.Inputs[name] = primaryInput(group)
+	}
+	return err
+}
+
+// fillNested fills the inputs under path from val, which must be a struct or a
+// pointer to a struct. Nil pointers leave the inputs untouched. visiting holds
+// the pointers which are currently being followed. A pointer which is already
+// being followed is skipped, so that a model which refers to itself (e.g. a
+// category whose parent is the category itself) does not recurse forever.
+func (form *Form) fillNested(val reflect.Value, path []pathSegment, visiting map[interface{}]bool) error {
+	if val.Kind() == reflect.Ptr && !val.IsNil() && val.CanInterface() {
+		ptr := val.Interface()
+		if visiting[ptr] {
+			return nil
+		}
+		visiting[ptr] = true
+		defer delete(visiting, ptr)
+	}
+	structVal, ok := dereference(val)
+	if !ok {
+		return nil
+	}
+	return form.fillStruct(structVal, path, visiting)
+}
+
+// fillStruct fills the inputs of form from the fields of structVal. path holds
+// the name segments which correspond to structVal itself. See bindStruct.
+func (form *Form) fillStruct(structVal reflect.Value, path []pathSegment, visiting map[interface{}]bool) error {
+	for _, sf := range structFields(structVal.Type()) {
+		field, tag := sf.field, sf.tag
+		fieldVal, ok := fieldByIndex(structVal, sf.index)
+		if !ok {
+			continue
+		}
+		fieldPath := appendPath(path, fieldSegments(field, tag)...)
+		switch {
+		case isInputFiller(field.Type):
+			// Like InputBinder in Bind, InputFiller takes precedence over
+			// the handling of nested structs and collections.
+			if group := form.groupForPath(fieldPath); group != nil {
+				if err := fillGroup(fieldVal, group); err != nil {
+					return err
+				}
+			}
+		case isNestedStruct(field.Type):
+			if err := form.fillNested(fieldVal, fieldPath, visiting); err != nil {
+				return err
+			}
+		case isCollection(field.Type):
+			if err := form.fillCollection(fieldVal, fieldPath, visiting); err != nil {
+				return err
+			}
+		default:
+			if group := form.groupForPath(fieldPath); group != nil {
+				if err := fillGroup(fieldVal, group); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// fieldByIndex returns the field of structVal identified by index, which may
+// be the index of a field promoted from an embedded struct. It returns false if
+// the field is promoted from a nil pointer to an embedded struct.
+func fieldByIndex(structVal reflect.Value, index []int) (reflect.Value, bool) {
+	fieldVal := structVal.Field(index[0])
+	for _, i := range index[1:] {
+		embedded, ok := dereference(fieldVal)
+		if !ok {
+			return reflect.Value{}, false
+		}
+		fieldVal = embedded.Field(i)
+	}
+	return fieldVal, true
+}
+
+// fillCollection fills the inputs under path from fieldVal, which must be a
+// slice, array, or map (or pointer to one). See bindCollection.
+func (form *Form) fillCollection(fieldVal reflect.Value, path []pathSegment, visiting map[interface{}]bool) error {
+	collectionVal, ok := dereference(fieldVal)
+	if !ok {
+		return nil
+	}
+	elemType := collectionVal.Type().Elem()
+	nested := isNestedStruct(elemType) && !isInputFiller(elemType)
+	if collectionVal.Kind() == reflect.Map {
+		keys := collectionVal.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+		for _, key := range keys {
+			elemPath := appendPath(path, pathSegment{name: key.String(), exact: true})
+			if err := form.fillEntry(collectionVal.MapIndex(key), elemPath, nested, visiting); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	// For slices and arrays, prefer indexed inputs (e.g. "items[0]") and fall
+	// back to repeated inputs (e.g. "tags" or "tags[]").
+	foundIndexed := false
+	for i := 0; i < collectionVal.Len(); i++ {
+		elemPath := appendPath(path, pathSegment{name: strconv.Itoa(i), exact: true})
+		if nested || form.groupForPath(elemPath) != nil {
+			foundIndexed = true
+			if err := form.fillEntry(collectionVal.Index(i), elemPath, nested, visiting); err != nil {
+				return err
+			}
+		}
+	}
+	if foundIndexed || nested {
+		return nil
+	}
+	group := form.groupForPath(path)
+	if group == nil {
+		group = form.groupForPath(appendPath(path, pathSegment{name: "", exact: true}))
+	}
+	if group == nil {
+		return nil
+	}
+	values := make([]string, 0, collectionVal.Len())
+	for i := 0; i < collectionVal.Len(); i++ {
+		value, ok, err := formatValue(collectionVal.Index(i), group[0])
+		if err != nil {
+			return err
+		}
+		if ok {
+			values = append(values, value)
+		}
+	}
+	fillValues(group, values)
+	return nil
+}
+
+// fillEntry fills the inputs for a single element of a collection.
+func (form *Form) fillEntry(elemVal reflect.Value, elemPath []pathSegment, nested bool, visiting map[interface{}]bool) error {
+	if nested {
+		return form.fillNested(elemVal, elemPath, visiting)
+	}
+	if group := form.groupForPath(elemPath); group != nil {
+		return fillGroup(elemVal, group)
+	}
+	return nil
+}
+
+// fillGroup fills the given group of inputs, which all share the same name,
+// from fieldVal.
+func fillGroup(fieldVal reflect.Value, group []*Input) error {
+	// Check if the field implements InputFiller. If it does, let it fill the
+	// primary input of the group.
+	if filler, ok := asInputFiller(fieldVal); ok {
+		input := primaryInput(group)
+		if err := filler.FillInput(input); err != nil {
+			return err
+		}
+		input.UpdateElement()
+		return nil
+	}
+	val, ok := dereference(fieldVal)
+	if !ok {
+		return nil
+	}
+	if val.Kind() == reflect.Bool {
+		switch group[0].Type {
+		case InputCheckbox, InputRadio:
+			for _, input := range group {
+				input.Checked = val.Bool()
+				input.UpdateElement()
+			}
+			return nil
+		}
+	}
+	value, ok, err := formatValue(val, group[0])
+	if err != nil || !ok {
+		return err
+	}
+	fillValues(group, []string{value})
+	return nil
+}
+
+// fillValues sets the values of the given group of inputs. Checkboxes and radio
+// buttons are checked iff their value is in values, and select elements have
+// the options whose values are in values selected. All other inputs are
+// assigned values in order, and are set to an empty string if there are more
+// inputs than values. Buttons are left untouched.
+func fillValues(group []*Input, values []string) {
+	next := 0
+	for _, input := range group {
+		switch input.Type {
+		case InputCheckbox, InputRadio:
+			input.Checked = containsString(values, input.RawValue)
+		case InputSelect, InputSelectMultiple:
+			input.SelectedValues = values
+			if input.Type == InputSelect && len(values) > 1 {
+				input.SelectedValues = values[:1]
+			}
+			input.RawValue = ""
+			if len(values) > 0 {
+				input.RawValue = values[0]
+			}
+		case InputButton, InputSubmit, InputReset, InputImage:
+			continue
+		default:
+			input.RawValue = ""
+			if next < len(values) {
+				input.RawValue = values[next]
+			}
+			next++
+		}
+		input.UpdateElement()
+	}
+}
+
+// formatValue formats val as an input value. input is used to determine the
+// format for time.Time values. It returns false if val is a nil pointer, and
+// an error if the type of val is not supported.
+func formatValue(val reflect.Value, input *Input) (string, bool, error) {
+	val, ok := dereference(val)
+	if !ok {
+		return "", false, nil
+	}
+	switch val.Type() {
+	case timeType:
+		return formatTime(val.Interface().(time.Time), input.Type), true, nil
+	case clockType:
+		return val.Interface().(Clock).String(), true, nil
+	case weekType:
+		return val.Interface().(Week).String(), true, nil
+	}
+	switch val.Kind() {
+	case reflect.String:
+		return val.String(), true, nil
+	case reflect.Slice:
+		if val.Type().Elem().Kind() == reflect.Uint8 {
+			return string(val.Bytes()), true, nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(val.Int(), 10), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(val.Uint(), 10), true, nil
+	case reflect.Float32:
+		return strconv.FormatFloat(val.Float(), 'f', -1, 32), true, nil
+	case reflect.Float64:
+		return strconv.FormatFloat(val.Float(), 'f', -1, 64), true, nil
+	case reflect.Bool:
+		return strconv.FormatBool(val.Bool()), true, nil
+	}
+	return "", false, fmt.Errorf(
+		"form: Don't know how to fill input %s from struct field of type %s",
+		input.Name,
+		val.Type().String())
+}
+
+// isInputFiller returns true iff fieldType or a pointer to it implements
+// InputFiller.
+func isInputFiller(fieldType reflect.Type) bool {
+	return fieldType.Implements(inputFillerType) || reflect.PtrTo(fieldType).Implements(inputFillerType)
+}
+
+// asInputFiller returns fieldVal (or its address) as an InputFiller if either
+// implements the interface.
+func asInputFiller(fieldVal reflect.Value) (InputFiller, bool) {
+	if fieldVal.Type().Implements(inputFillerType) {
+		if fieldVal.Kind() == reflect.Ptr && fieldVal.IsNil() {
+			return nil, false
+		}
+		return fieldVal.Interface().(InputFiller), true
+	}
+	if fieldVal.CanAddr() && reflect.PtrTo(fieldVal.Type()).Implements(inputFillerType) {
+		return fieldVal.Addr().Interface().(InputFiller), true
+	}
+	return nil, false
+}
+
+// dereference follows pointers until it reaches a value which is not a
+// pointer. It returns false if any of the pointers are nil.
+func dereference(val reflect.Value) (reflect.Value, bool) {
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return val, false
+		}
+		val = val.Elem()
+	}
+	return val, true
+}
+
+// groupForPath returns the group of inputs whose name matches path, or nil if
+// there is no such group. A name which matches path exactly is preferred over
+// one which only matches when ignoring case.
+func (form *Form) groupForPath(path []pathSegment) []*Input {
+	names := sortedInputNames(form.Inputs)
+	for _, exactOnly := range []bool{true, false} {
+		for _, name := range names {
+			if pathMatches(path, splitInputName(name), exactOnly) {
+				return form.Groups[name]
+			}
+		}
+	}
+	return nil
+}
+
+// containsString returns true iff s is in list.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,290 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newWritableTestInput creates an Input backed by a writable testElement so
+// that tests can check what Fill writes back to the element.
+func newWritableTestInput(name string, typ InputType, value string) *Input {
+	return NewInput(&testElement{name: name, typ: typ, value: value})
+}
+
+// FillInput implements the FillInput method of InputFiller. It is the inverse
+// of fullName.BindInput.
+func (name fullName) FillInput(input *Input) error {
+	input.RawValue = name.First + " " + name.Last
+	return nil
+}
+
+func TestFill(t *testing.T) {
+	form := NewForm(
+		newWritableTestInput("string", InputText, ""),
+		newWritableTestInput("int", InputNumber, ""),
+		newWritableTestInput("float", InputNumber, ""),
+		newWritableTestInput("bool", InputCheckbox, "on"),
+		newWritableTestInput("date", InputDate, ""),
+		newWritableTestInput("zero", InputDate, "2000-01-01"),
+		newWritableTestInput("color", InputRadio, "red"),
+		newWritableTestInput("color", InputRadio, "green"),
+		newWritableTestInput("tags", InputCheckbox, "a"),
+		newWritableTestInput("tags", InputCheckbox, "b"),
+		newWritableTestInput("tags", InputCheckbox, "c"),
+		newWritableTestInput("size", InputSelect, ""),
+		newWritableTestInput("billing.street", InputText, ""),
+		newWritableTestInput("items[0].quantity", InputNumber, ""),
+		newWritableTestInput("items[1].quantity", InputNumber, ""),
+		newWritableTestInput("first-name", InputText, "untouched"),
+		newWritableTestInput("name", InputText, ""),
+		newWritableTestInput("nil", InputText, "untouched"),
+	)
+	source := struct {
+		String    string
+		Int       int
+		Float     float64
+		Bool      bool
+		Date      time.Time
+		Zero      time.Time
+		Color     string
+		Tags      []string
+		Size      string
+		Billing   address
+		Items     []lineItem
+		FirstName string `form:"-"`
+		Name      fullName
+		Nil       *string
+	}{
+		String:  "foo",
+		Int:     42,
+		Float:   3.5,
+		Bool:    true,
+		Date:    mustParseTime("2006-01-02", "2015-06-01"),
+		Color:   "green",
+		Tags:    []string{"a", "c"},
+		Size:    "large",
+		Billing: address{Street: "1 Main St"},
+		Items:   []lineItem{{Quantity: 3}, {Quantity: 5}},
+		Name:    fullName{First: "Foo", Last: "Bar"},
+	}
+	if err := form.Fill(&source); err != nil {
+		t.Fatalf("Unexpected error from Fill: %s", err)
+	}
+	expectedValues := map[string]string{
+		"string":            "foo",
+		"int":               "42",
+		"float":             "3.5",
+		"date":              "2015-06-01",
+		"zero":              "",
+		"color":             "green",
+		"size":              "large",
+		"billing.street":    "1 Main St",
+		"items[0].quantity": "3",
+		"items[1].quantity": "5",
+		"first-name":        "untouched",
+		"name":              "Foo Bar",
+		"nil":               "untouched",
+	}
+	for name, expected := range expectedValues {
+		input := form.Inputs[name]
+		if input.RawValue != expected {
+			t.Errorf("Incorrect RawValue for input %s. Expected %q but got %q", name, expected, input.RawValue)
+		}
+		// Check that the value was written to the element too. Radio buttons
+		// and select elements are checked separately below.
+		if input.Type == InputRadio || input.Type == InputSelect {
+			continue
+		}
+		if got := input.El.Value(); got != expected {
+			t.Errorf("Incorrect element value for input %s. Expected %q but got %q", name, expected, got)
+		}
+	}
+	if !form.Inputs["bool"].El.Checked() {
+		t.Error("Expected bool checkbox to be checked")
+	}
+	if !reflect.DeepEqual(form.Inputs["size"].El.SelectedValues(), []string{"large"}) {
+		t.Errorf("Expected size to have large selected but got %v", form.Inputs["size"].El.SelectedValues())
+	}
+	if tags, _ := form.GetStrings("tags"); !reflect.DeepEqual(tags, []string{"a", "c"}) {
+		t.Errorf("Expected tags a and c to be checked but got %v", tags)
+	}
+	for _, input := range form.Groups["color"] {
+		if expected := input.RawValue == "green"; input.El.Checked() != expected {
+			t.Errorf("Expected radio %s to have checked = %v", input.RawValue, expected)
+		}
+	}
+}
+
+func TestFillRoundTrip(t *testing.T) {
+	// Filling a form and then binding it should result in the original struct.
+	form := newBindTestForm()
+	source := struct {
+		String  string
+		Bytes   []byte
+		Int     int
+		Uint8   uint8
+		Float32 float32
+		Bool    bool
+	}{"bar", []byte("baz"), -7, 255, 1.25, false}
+	if err := form.Fill(source); err != nil {
+		t.Fatalf("Unexpected error from Fill: %s", err)
+	}
+	target := source
+	target.String, target.Bytes, target.Int, target.Uint8, target.Float32, target.Bool = "", nil, 0, 0, 0, true
+	if err := form.Bind(&target); err != nil {
+		t.Fatalf("Unexpected error from Bind: %s", err)
+	}
+	if !reflect.DeepEqual(target, source) {
+		t.Errorf("Round trip failed.\nExpected: %+v\nBut got:  %+v", source, target)
+	}
+}
+
+func TestFillClockAndWeek(t *testing.T) {
+	form := NewForm(
+		newWritableTestInput("opens", InputTime, ""),
+		newWritableTestInput("week", InputWeek, ""),
+		newWritableTestInput("month", InputMonth, ""),
+	)
+	source := struct {
+		Opens Clock
+		Week  Week
+		Month time.Time
+	}{
+		Opens: Clock{Hour: 8, Minute: 30},
+		Week:  Week{Year: 2015, Week: 23},
+		Month: mustParseTime("2006-01-02", "2015-06-01"),
+	}
+	if err := form.Fill(source); err != nil {
+		t.Fatalf("Unexpected error from Fill: %s", err)
+	}
+	expectedValues := map[string]string{
+		"opens": "08:30",
+		"week":  "2015-W23",
+		"month": "2015-06",
+	}
+	for name, expected := range expectedValues {
+		if got := form.Inputs[name].El.Value(); got != expected {
+			t.Errorf("Incorrect element value for input %s. Expected %q but got %q", name, expected, got)
+		}
+	}
+}
+
+func TestFillErrors(t *testing.T) {
+	form := NewForm(newWritableTestInput("ch", InputText, ""))
+	notStruct := 0
+	testCases := []struct {
+		v    interface{}
+		desc string
+	}{
+		{notStruct, "non-struct"},
+		{(*struct{})(nil), "nil pointer"},
+		{struct{ Ch chan int }{make(chan int)}, "unsupported field type"},
+	}
+	for _, tc := range testCases {
+		if err := form.Fill(tc.v); err == nil {
+			t.Errorf("Expected an error from Fill for %s but got none", tc.desc)
+		}
+	}
+}
+
+// upperFiller implements Filler.
+type upperFiller struct {
+	Name string
+}
+
+// FillForm implements the FillForm method of Filler.
+func (f upperFiller) FillForm(form *Form) error {
+	input := form.Inputs["name"]
+	input.RawValue = strings.ToUpper(f.Name)
+	input.UpdateElement()
+	return nil
+}
+
+func TestFiller(t *testing.T) {
+	form := NewForm(newWritableTestInput("name", InputText, ""))
+	if err := form.Fill(upperFiller{Name: "foo"}); err != nil {
+		t.Fatalf("Unexpected error from Fill: %s", err)
+	}
+	if got := form.Inputs["name"].El.Value(); got != "FOO" {
+		t.Errorf("Expected element value to be %q but got %q", "FOO", got)
+	}
+}
+
+// fillOnlyName is a struct which implements InputFiller but not InputBinder.
+type fillOnlyName struct {
+	First string
+	Last  string
+}
+
+func (name fillOnlyName) FillInput(input *Input) error {
+	input.RawValue = name.First + " " + name.Last
+	return nil
+}
+
+// joinedTags is a slice which implements InputFiller by joining its elements
+// with commas.
+type joinedTags []string
+
+func (tags joinedTags) FillInput(input *Input) error {
+	input.RawValue = strings.Join(tags, ",")
+	return nil
+}
+
+func TestInputFillerStructAndSlice(t *testing.T) {
+	form := NewForm(
+		newWritableTestInput("name", InputText, ""),
+		newWritableTestInput("tags", InputText, ""),
+	)
+	source := struct {
+		Name fillOnlyName
+		Tags joinedTags
+	}{
+		Name: fillOnlyName{First: "Foo", Last: "Bar"},
+		Tags: joinedTags{"x", "y"},
+	}
+	if err := form.Fill(source); err != nil {
+		t.Fatalf("Unexpected error from Fill: %s", err)
+	}
+	if got := form.Inputs["name"].El.Value(); got != "Foo Bar" {
+		t.Errorf("Expected name to be %q but got %q", "Foo Bar", got)
+	}
+	if got := form.Inputs["tags"].El.Value(); got != "x,y" {
+		t.Errorf("Expected tags to be %q but got %q", "x,y", got)
+	}
+}
+
+type fillNode struct {
+	Name   string
+	Parent *fillNode
+}
+
+func TestFillCycle(t *testing.T) {
+	form := NewForm(
+		newWritableTestInput("name", InputText, ""),
+		newWritableTestInput("parent.name", InputText, ""),
+		newWritableTestInput("parent.parent.name", InputText, "unchanged"),
+	)
+	child := &fillNode{Name: "child"}
+	parent := &fillNode{Name: "parent", Parent: child}
+	child.Parent = parent
+	if err := form.Fill(child); err != nil {
+		t.Fatalf("Unexpected error from Fill: %s", err)
+	}
+	expected := map[string]string{
+		"name":               "child",
+		"parent.name":        "parent",
+		"parent.parent.name": "unchanged",
+	}
+	for name, value := range expected {
+		if got := form.Inputs[name].El.Value(); got != value {
+			t.Errorf("Expected %s to be %q but got %q", name, value, got)
+		}
+	}
+}
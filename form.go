@@ -7,9 +7,9 @@ package form
 
 import (
 	"fmt"
+	"net/url"
+	"strconv"
 	"time"
-
-	"honnef.co/go/js/dom"
 )
 
 // InputNotFoundError is returned whenever form.GetX is called with an
@@ -33,37 +33,92 @@ func (e InputNotFoundError) Error() string {
 
 // Form is a go representation of an html input form.
 type Form struct {
+	// Inputs holds a single input for each distinct input name in the form.
+	// When more than one input shares the same name (e.g. a group of radio
+	// buttons), Inputs holds the first one whose value would be submitted by the
+	// browser (e.g. the checked radio button). If none of them would be
+	// submitted, Inputs holds the first one in document order, but the getters
+	// (e.g. GetString), the validations, and Bind treat its value as empty.
+	// Buttons are the exception: their value is only submitted when they are
+	// used to submit the form, so they are left out of Values, but the getters,
+	// the validations, and Bind use their value as is.
 	Inputs map[string]*Input
+	// Groups holds every input in the form, keyed by input name and in document
+	// order. Use Groups when you need access to all the inputs which share a
+	// name, e.g. all the checkboxes in a checkbox group.
+	Groups map[string][]*Input
 	Errors []error
+	// Labels holds human-readable labels for inputs, keyed by input name. The
+	// labels are used instead of the input names in the messages of the
+	// built-in validations. See Form.Label for other sources of labels.
+	Labels map[string]string
+	// Catalog, if non-nil, holds message templates which replace the default
+	// messages of the built-in validations. Messages passed explicitly to the
+	// "f" variants of the validations (e.g. Requiredf) are never replaced.
+	Catalog *Catalog
+	// Locale is the locale used to look up templates in Catalog, e.g. "en" or
+	// "pt-BR".
+	Locale string
 }
 
-// Parse creates a and returns a Form object from the given form element.
-func Parse(formElement dom.Element) (*Form, error) {
+// NewForm creates and returns a Form object containing the given inputs, in
+// order. It is useful when the inputs do not come from an html form element,
+// e.g. when they are created with NewInput from a custom Element.
+func NewForm(inputs ...*Input) *Form {
 	form := &Form{
 		Inputs: map[string]*Input{},
+		Groups: map[string][]*Input{},
 	}
-	htmlFormElement, ok := formElement.(*dom.HTMLFormElement)
-	if !ok {
-		return nil, fmt.Errorf("form: Argument to Parse must be a *dom.HTMLFormElement. (Got %T)", formElement)
+	for _, input := range inputs {
+		form.addInput(input)
 	}
-	for _, el := range htmlFormElement.Elements() {
-		// Cast the element to an input element.
-		inputEl, ok := el.(*dom.HTMLInputElement)
-		if !ok {
-			// Skip elements which are not input elements.
-			continue
+	return form
+}
+
+// addInput adds input to the form. It always adds input to the corresponding
+// group in form.Groups, and replaces the input in form.Inputs iff there is not
+// already an input with the same name which would be submitted.
+func (form *Form) addInput(input *Input) {
+	form.Groups[input.Name] = append(form.Groups[input.Name], input)
+	form.Inputs[input.Name] = primaryInput(form.Groups[input.Name])
+}
+
+// primaryInput returns the input from group which belongs in form.Inputs, i.e.
+// the first one which would be submitted, or the first one in the group if
+// none of them would be submitted.
+func primaryInput(group []*Input) *Input {
+	for _, input := range group {
+		if input.isSubmitted() {
+			return input
 		}
-		form.Inputs[inputEl.Name] = NewInput(inputEl)
 	}
-	return form, nil
+	return group[0]
+}
+
+// submittedInput returns the input identified by inputName from form.Inputs. If
+// the browser would not submit the input (e.g. none of the radio buttons in a
+// group is checked), it returns a copy of the input with an empty RawValue.
+// Buttons keep their RawValue (see Input.isOmitted). It returns an
+// InputNotFoundError if there is no input with the given inputName.
+func (form *Form) submittedInput(inputName string) (*Input, error) {
+	input, found := form.Inputs[inputName]
+	if !found {
+		return nil, newInputNotFoundError(inputName)
+	}
+	if input.isOmitted() {
+		submitted := *input
+		submitted.RawValue = ""
+		return &submitted, nil
+	}
+	return input, nil
 }
 
 // GetString returns the value of the input identified by inputName. It returns
 // an InputNotFoundError if there is no input with the given inputName.
 func (form *Form) GetString(inputName string) (string, error) {
-	input, found := form.Inputs[inputName]
-	if !found {
-		return "", newInputNotFoundError(inputName)
+	input, err := form.submittedInput(inputName)
+	if err != nil {
+		return "", err
 	}
 	return input.RawValue, nil
 }
@@ -72,9 +127,9 @@ func (form *Form) GetString(inputName string) (string, error) {
 // to an int. It returns an error if the input is not found or if the input
 // value could not be converted to an int.
 func (form *Form) GetInt(inputName string) (int, error) {
-	input, found := form.Inputs[inputName]
-	if !found {
-		return 0, newInputNotFoundError(inputName)
+	input, err := form.submittedInput(inputName)
+	if err != nil {
+		return 0, err
 	}
 	return input.Int()
 }
@@ -83,9 +138,9 @@ func (form *Form) GetInt(inputName string) (int, error) {
 // to a uint. It returns an error if the input is not found or if the input
 // value could not be converted to a uint.
 func (form *Form) GetUint(inputName string) (uint, error) {
-	input, found := form.Inputs[inputName]
-	if !found {
-		return 0, newInputNotFoundError(inputName)
+	input, err := form.submittedInput(inputName)
+	if err != nil {
+		return 0, err
 	}
 	return input.Uint()
 }
@@ -94,9 +149,9 @@ func (form *Form) GetUint(inputName string) (uint, error) {
 // to a float. It returns an error if the input is not found or if the input
 // value could not be converted to a float.
 func (form *Form) GetFloat(inputName string) (float64, error) {
-	input, found := form.Inputs[inputName]
-	if !found {
-		return 0, newInputNotFoundError(inputName)
+	input, err := form.submittedInput(inputName)
+	if err != nil {
+		return 0, err
 	}
 	return input.Float()
 }
@@ -105,28 +160,211 @@ func (form *Form) GetFloat(inputName string) (float64, error) {
 // to a bool. It returns an error if the input is not found or if the input
 // value could not be converted to a bool.
 func (form *Form) GetBool(inputName string) (bool, error) {
-	input, found := form.Inputs[inputName]
-	if !found {
-		return false, newInputNotFoundError(inputName)
+	input, err := form.submittedInput(inputName)
+	if err != nil {
+		return false, err
 	}
 	return input.Bool()
 }
 
 // GetTime returns the value of the input identified by inputName converted to a
-// time.Time. GetTime supports the time, date, and datetime input types and
-// assumes the input value adheres to the rfc3339 standard (the default for form
-// inputs). If the type of the input is anything else, it will attempt to parse
-// it as an rfc3339 datetime. It returns an error if the input is not found or
-// if the input value could not be converted to a time.Time.
+// time.Time. See Input.Time for the supported input types and formats. It
+// returns an error if the input is not found or if the input value could not be
+// converted to a time.Time.
 func (form *Form) GetTime(inputName string) (time.Time, error) {
-	input, found := form.Inputs[inputName]
-	if !found {
-		return time.Time{}, newInputNotFoundError(inputName)
+	input, err := form.submittedInput(inputName)
+	if err != nil {
+		return time.Time{}, err
 	}
 	return input.Time()
 }
 
+// GetClock returns the value of the input identified by inputName converted to
+// a Clock. It returns an error if the input is not found or if the input value
+// could not be converted to a Clock.
+func (form *Form) GetClock(inputName string) (Clock, error) {
+	input, err := form.submittedInput(inputName)
+	if err != nil {
+		return Clock{}, err
+	}
+	return input.Clock()
+}
+
+// GetWeek returns the value of the input identified by inputName converted to a
+// Week. It returns an error if the input is not found or if the input value
+// could not be converted to a Week.
+func (form *Form) GetWeek(inputName string) (Week, error) {
+	input, err := form.submittedInput(inputName)
+	if err != nil {
+		return Week{}, err
+	}
+	return input.Week()
+}
+
+// GetStrings returns the values that the browser would submit for all the
+// inputs identified by inputName. Checkboxes and radio buttons which are not
+// checked are not included, and select elements contribute all of their
+// selected values. It returns an InputNotFoundError if there is no input with
+// the given inputName.
+func (form *Form) GetStrings(inputName string) ([]string, error) {
+	group, found := form.Groups[inputName]
+	if !found {
+		return nil, newInputNotFoundError(inputName)
+	}
+	values := []string{}
+	for _, input := range group {
+		values = append(values, input.Values()...)
+	}
+	return values, nil
+}
+
+// GetInts is like GetStrings but converts each value to an int. It returns an
+// error if the input is not found or if any of the values could not be
+// converted to an int.
+func (form *Form) GetInts(inputName string) ([]int, error) {
+	values, err := form.GetStrings(inputName)
+	if err != nil {
+		return nil, err
+	}
+	ints := make([]int, len(values))
+	for i, value := range values {
+		ints[i], err = strconv.Atoi(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ints, nil
+}
+
+// GetUints is like GetStrings but converts each value to a uint. It returns an
+// error if the input is not found or if any of the values could not be
+// converted to a uint.
+func (form *Form) GetUints(inputName string) ([]uint, error) {
+	values, err := form.GetStrings(inputName)
+	if err != nil {
+		return nil, err
+	}
+	uints := make([]uint, len(values))
+	for i, value := range values {
+		u, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		uints[i] = uint(u)
+	}
+	return uints, nil
+}
+
+// GetFloats is like GetStrings but converts each value to a float64. It returns
+// an error if the input is not found or if any of the values could not be
+// converted to a float64.
+func (form *Form) GetFloats(inputName string) ([]float64, error) {
+	values, err := form.GetStrings(inputName)
+	if err != nil {
+		return nil, err
+	}
+	floats := make([]float64, len(values))
+	for i, value := range values {
+		floats[i], err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return floats, nil
+}
+
+// Values returns all of the values that the browser would submit for the form,
+// keyed by input name. Names for which no value would be submitted (e.g. a
+// group of unchecked checkboxes) are not included.
+func (form *Form) Values() url.Values {
+	values := url.Values{}
+	for name, group := range form.Groups {
+		for _, input := range group {
+			for _, value := range input.Values() {
+				values.Add(name, value)
+			}
+		}
+	}
+	return values
+}
+
 // HasErrors returns true if the form has at least one validation error.
 func (form *Form) HasErrors() bool {
 	return len(form.Errors) > 0
 }
+
+// asValidationError returns err as a *ValidationError and true, or false if err
+// is not a ValidationError.
+func asValidationError(err error) (*ValidationError, bool) {
+	switch valErr := err.(type) {
+	case *ValidationError:
+		return valErr, true
+	case ValidationError:
+		return &valErr, true
+	}
+	return nil, false
+}
+
+// ErrorsFor returns the validation errors for the input identified by
+// inputName, in the order they were added. Errors in form.Errors which are not
+// ValidationErrors are ignored. It returns nil if the input has no errors.
+func (form *Form) ErrorsFor(inputName string) []*ValidationError {
+	var errs []*ValidationError
+	for _, err := range form.Errors {
+		if valErr, ok := asValidationError(err); ok && valErr.InputName == inputName {
+			errs = append(errs, valErr)
+		}
+	}
+	return errs
+}
+
+// FirstError returns the first validation error for the input identified by
+// inputName, or nil if the input has no errors. It is useful for showing a
+// single message next to each input.
+func (form *Form) FirstError(inputName string) *ValidationError {
+	for _, err := range form.Errors {
+		if valErr, ok := asValidationError(err); ok && valErr.InputName == inputName {
+			return valErr
+		}
+	}
+	return nil
+}
+
+// HasErrorsFor returns true if the input identified by inputName has at least
+// one validation error.
+func (form *Form) HasErrorsFor(inputName string) bool {
+	return form.FirstError(inputName) != nil
+}
+
+// ErrorsByInput returns the validation errors in the form grouped by input
+// name. The errors for each input are in the order they were added. Errors in
+// form.Errors which are not ValidationErrors are ignored.
+func (form *Form) ErrorsByInput() map[string][]*ValidationError {
+	errs := map[string][]*ValidationError{}
+	for _, err := range form.Errors {
+		if valErr, ok := asValidationError(err); ok {
+			errs[valErr.InputName] = append(errs[valErr.InputName], valErr)
+		}
+	}
+	return errs
+}
+
+// ClearErrors removes all the errors from the form, so that it can be
+// validated again without parsing it again.
+func (form *Form) ClearErrors() {
+	form.Errors = nil
+}
+
+// ClearErrorsFor removes the validation errors for the input identified by
+// inputName from the form, leaving the errors for all other inputs untouched.
+// It is useful for validating a single input again, e.g. after it changed.
+func (form *Form) ClearErrorsFor(inputName string) {
+	var errs []error
+	for _, err := range form.Errors {
+		if valErr, ok := asValidationError(err); ok && valErr.InputName == inputName {
+			continue
+		}
+		errs = append(errs, err)
+	}
+	form.Errors = errs
+}
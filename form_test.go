@@ -0,0 +1,361 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testElement is an Element which stands in for an html element in tests so
+// that they can run without a browser.
+type testElement struct {
+	name           string
+	typ            InputType
+	value          string
+	checked        bool
+	selectedValues []string
+	attrs          map[string]string
+}
+
+func (el testElement) Name() string             { return el.name }
+func (el testElement) Type() InputType          { return el.typ }
+func (el testElement) Value() string            { return el.value }
+func (el testElement) SelectedValues() []string { return el.selectedValues }
+func (el testElement) Checked() bool            { return el.checked }
+
+func (el testElement) GetAttribute(name string) string {
+	return el.attrs[name]
+}
+
+func (el testElement) HasAttribute(name string) bool {
+	_, found := el.attrs[name]
+	return found
+}
+
+// SetValue satisfies the SetValue method of WritableElement.
+func (el *testElement) SetValue(value string) { el.value = value }
+
+// SetChecked satisfies the SetChecked method of WritableElement.
+func (el *testElement) SetChecked(checked bool) { el.checked = checked }
+
+// SetSelectedValues satisfies the SetSelectedValues method of WritableElement.
+func (el *testElement) SetSelectedValues(values []string) { el.selectedValues = values }
+
+// newTestInput creates an Input backed by a testElement with the given name,
+// type, and value.
+func newTestInput(name string, typ InputType, value string) *Input {
+	return NewInput(testElement{name: name, typ: typ, value: value})
+}
+
+// newCheckedTestInput is like newTestInput but also sets the checked state.
+func newCheckedTestInput(name string, typ InputType, value string, checked bool) *Input {
+	return NewInput(testElement{name: name, typ: typ, value: value, checked: checked})
+}
+
+func mustParseTime(layout string, value string) time.Time {
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestGetString(t *testing.T) {
+	// All the input types here should be convertible to strings via GetString.
+	form := NewForm(
+		newTestInput("default", InputDefault, "foo"),
+		newTestInput("email", InputEmail, "foo@example.com"),
+		newTestInput("secret", InputHidden, "this is a secret"),
+		newTestInput("password", InputPassword, "password123"),
+		newTestInput("search", InputSearch, "foo bar"),
+		newTestInput("phone", InputTel, "867-5309"),
+		newTestInput("text", InputText, "This is some text."),
+		newTestInput("url", InputURL, "http://example.com"),
+		newTestInput("textarea", InputTextArea, "Some longer text."),
+	)
+	expectedValues := map[string]string{
+		"default":  "foo",
+		"email":    "foo@example.com",
+		"secret":   "this is a secret",
+		"password": "password123",
+		"search":   "foo bar",
+		"phone":    "867-5309",
+		"text":     "This is some text.",
+		"url":      "http://example.com",
+		"textarea": "Some longer text.",
+	}
+	for name, expected := range expectedValues {
+		got, err := form.GetString(name)
+		if err != nil {
+			t.Errorf("Unexpected error for input %s: %s", name, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("Incorrect value for input %s. Expected %q but got %q", name, expected, got)
+		}
+	}
+	if _, err := form.GetString("non-existing"); err == nil {
+		t.Error("Expected an InputNotFoundError for a non-existing input but got none")
+	} else if _, ok := err.(InputNotFoundError); !ok {
+		t.Errorf("Expected an InputNotFoundError but got %T: %s", err, err)
+	}
+}
+
+func TestGetNumbers(t *testing.T) {
+	testCases := []struct {
+		typ      InputType
+		value    string
+		get      func(form *Form) (interface{}, error)
+		expected interface{}
+		wantErr  bool
+	}{
+		{InputDefault, "23", getInt, 23, false},
+		{InputTel, "8675309", getInt, 8675309, false},
+		{InputText, "-789", getInt, -789, false},
+		{InputNumber, "123456789", getInt, 123456789, false},
+		{InputNumber, "foo", getInt, nil, true},
+		{InputDefault, "23", getUint, uint(23), false},
+		{InputNumber, "123456789", getUint, uint(123456789), false},
+		{InputText, "-789", getUint, nil, true},
+		{InputDefault, "23.0", getFloat, 23.0, false},
+		{InputText, "789.6", getFloat, 789.6, false},
+		{InputNumber, "123456789.7", getFloat, 123456789.7, false},
+		{InputNumber, "foo", getFloat, nil, true},
+	}
+	for i, tc := range testCases {
+		form := NewForm(newTestInput("test", tc.typ, tc.value))
+		got, err := tc.get(form)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("Test case %d: expected an error for value %q but got none", i, tc.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test case %d: unexpected error: %s", i, err)
+			continue
+		}
+		if got != tc.expected {
+			t.Errorf("Test case %d: expected %v (%T) but got %v (%T)", i, tc.expected, tc.expected, got, got)
+		}
+	}
+}
+
+func getInt(form *Form) (interface{}, error)   { return form.GetInt("test") }
+func getUint(form *Form) (interface{}, error)  { return form.GetUint("test") }
+func getFloat(form *Form) (interface{}, error) { return form.GetFloat("test") }
+
+func TestGetBool(t *testing.T) {
+	form := NewForm(
+		newTestInput("default", InputDefault, "true"),
+		newTestInput("text", InputText, "true"),
+		newCheckedTestInput("checkbox", InputCheckbox, "on", true),
+		newCheckedTestInput("radio", InputRadio, "on", true),
+		newCheckedTestInput("checkbox-false", InputCheckbox, "on", false),
+		newCheckedTestInput("radio-false", InputRadio, "on", false),
+	)
+	expectedValues := map[string]bool{
+		"default":        true,
+		"text":           true,
+		"checkbox":       true,
+		"radio":          true,
+		"checkbox-false": false,
+		"radio-false":    false,
+	}
+	for name, expected := range expectedValues {
+		got, err := form.GetBool(name)
+		if err != nil {
+			t.Errorf("Unexpected error for input %s: %s", name, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("Incorrect value for input %s. Expected %v but got %v", name, expected, got)
+		}
+	}
+}
+
+func TestGetTime(t *testing.T) {
+	form := NewForm(
+		newTestInput("date", InputDate, "1992-09-29"),
+		newTestInput("datetime", InputDateTime, "1985-12-03T23:59:34-08:00"),
+		newTestInput("datetime-local", InputDateTimeLocal, "1985-04-12T23:20:50.52"),
+		newTestInput("month", InputMonth, "1992-09"),
+		newTestInput("week", InputWeek, "2015-W01"),
+		newTestInput("time", InputTime, "23:20"),
+		newTestInput("time-seconds", InputTime, "23:20:50.52"),
+	)
+	expectedValues := map[string]time.Time{
+		"date":           mustParseTime("2006-01-02", "1992-09-29"),
+		"datetime":       mustParseTime(time.RFC3339, "1985-12-03T23:59:34-08:00"),
+		"datetime-local": mustParseTime("2006-01-02T15:04:05.999999999", "1985-04-12T23:20:50.52"),
+		"month":          mustParseTime("2006-01-02", "1992-09-01"),
+		"week":           mustParseTime("2006-01-02", "2014-12-29"),
+		"time":           mustParseTime("15:04", "23:20"),
+		"time-seconds":   mustParseTime("15:04:05.999999999", "23:20:50.52"),
+	}
+	for name, expected := range expectedValues {
+		got, err := form.GetTime(name)
+		if err != nil {
+			t.Errorf("Unexpected error for input %s: %s", name, err)
+			continue
+		}
+		if !got.Equal(expected) {
+			t.Errorf("Incorrect value for input %s. Expected %v but got %v", name, expected, got)
+		}
+	}
+}
+
+func TestMultipleInputsWithSameName(t *testing.T) {
+	form := NewForm(
+		newCheckedTestInput("color", InputRadio, "red", false),
+		newCheckedTestInput("color", InputRadio, "green", true),
+		newCheckedTestInput("color", InputRadio, "blue", false),
+		newCheckedTestInput("ids", InputCheckbox, "1", true),
+		newCheckedTestInput("ids", InputCheckbox, "2", false),
+		newCheckedTestInput("ids", InputCheckbox, "3", true),
+		newCheckedTestInput("none", InputCheckbox, "1", false),
+		newTestInput("tags", InputText, "foo"),
+		newTestInput("tags", InputText, "bar"),
+		NewInput(testElement{name: "sizes", typ: InputSelectMultiple, value: "s", selectedValues: []string{"s", "l"}}),
+		newTestInput("submit", InputSubmit, "save"),
+	)
+	// The checked radio button should be the one in form.Inputs.
+	if got, _ := form.GetString("color"); got != "green" {
+		t.Errorf("Expected color to be green but got %q", got)
+	}
+	if len(form.Groups["color"]) != 3 {
+		t.Errorf("Expected 3 inputs in the color group but got %d", len(form.Groups["color"]))
+	}
+	ids, err := form.GetInts("ids")
+	if err != nil {
+		t.Fatalf("Unexpected error from GetInts: %s", err)
+	}
+	if !reflect.DeepEqual(ids, []int{1, 3}) {
+		t.Errorf("Expected ids to be [1 3] but got %v", ids)
+	}
+	expectedValues := url.Values{
+		"color": {"green"},
+		"ids":   {"1", "3"},
+		"tags":  {"foo", "bar"},
+		"sizes": {"s", "l"},
+	}
+	if got := form.Values(); !reflect.DeepEqual(got, expectedValues) {
+		t.Errorf("Incorrect form values.\nExpected: %v\nBut got:  %v", expectedValues, got)
+	}
+	if _, err := form.GetStrings("non-existing"); err == nil {
+		t.Error("Expected an error for a non-existing input but got none")
+	}
+	if _, err := form.GetInts("tags"); err == nil {
+		t.Error("Expected an error when converting non-integer values but got none")
+	}
+}
+
+func TestErrorLookup(t *testing.T) {
+	form := NewForm(
+		newTestInput("name", InputText, ""),
+		newTestInput("age", InputNumber, "foo"),
+		newTestInput("email", InputEmail, "foo@example.com"),
+	)
+	form.Validate("name").Required().MinLength(3)
+	form.Validate("age").Required().IsInt().Greater(0)
+	form.Validate("email").Required().IsEmail()
+	form.Validate("name").AddError("name is taken.")
+	form.Errors = append(form.Errors, errors.New("some other error"))
+
+	if got := form.ErrorsFor("name"); len(got) != 2 || got[0].Code != RuleRequired || got[1].Error() != "name is taken." {
+		t.Errorf("Incorrect errors for name: %v", got)
+	}
+	if got := form.ErrorsFor("email"); got != nil {
+		t.Errorf("Expected no errors for email but got %v", got)
+	}
+	if got := form.FirstError("age"); got == nil || got.Code != RuleInteger {
+		t.Errorf("Incorrect first error for age: %v", got)
+	}
+	if got := form.FirstError("email"); got != nil {
+		t.Errorf("Expected no first error for email but got %v", got)
+	}
+	if !form.HasErrorsFor("name") || form.HasErrorsFor("email") || form.HasErrorsFor("missing") {
+		t.Error("HasErrorsFor returned the wrong result")
+	}
+	byInput := form.ErrorsByInput()
+	if len(byInput) != 2 || len(byInput["name"]) != 2 || len(byInput["age"]) != 2 {
+		t.Errorf("Incorrect errors by input: %v", byInput)
+	}
+
+	form.ClearErrorsFor("name")
+	if form.HasErrorsFor("name") || !form.HasErrorsFor("age") {
+		t.Error("ClearErrorsFor removed the wrong errors")
+	}
+	if len(form.Errors) != 3 {
+		t.Errorf("Expected 3 remaining errors but got %d", len(form.Errors))
+	}
+	form.ClearErrors()
+	if form.HasErrors() {
+		t.Errorf("Expected no errors after ClearErrors but got %v", form.Errors)
+	}
+}
+
+func TestUnsubmittedInputs(t *testing.T) {
+	form := NewForm(
+		newCheckedTestInput("color", InputRadio, "red", false),
+		newCheckedTestInput("color", InputRadio, "blue", false),
+		newCheckedTestInput("agree", InputCheckbox, "on", false),
+		newTestInput("other", InputText, ""),
+	)
+	if got, err := form.GetString("color"); err != nil || got != "" {
+		t.Errorf("Expected an empty value for color but got %q (err: %v)", got, err)
+	}
+	if got, err := form.GetBool("agree"); err != nil || got {
+		t.Errorf("Expected false for agree but got %v (err: %v)", got, err)
+	}
+	form.Validate("color").Required()
+	form.Validate("agree").Required()
+	form.Validate("color").EqualTo("other")
+	if len(form.Errors) != 2 || !form.HasErrorsFor("color") || !form.HasErrorsFor("agree") {
+		t.Errorf("Expected required errors for color and agree only but got %v", form.Errors)
+	}
+	target := struct {
+		Color string
+		Agree bool
+	}{Color: "green", Agree: true}
+	if err := form.Bind(&target); err != nil {
+		t.Fatalf("Unexpected error in Bind: %s", err)
+	}
+	if target.Color != "green" {
+		t.Errorf("Expected Color to be left untouched but got %q", target.Color)
+	}
+	if target.Agree {
+		t.Error("Expected Agree to be set to false by the unchecked checkbox")
+	}
+}
+
+func TestButtonInput(t *testing.T) {
+	form := NewForm(newTestInput("button", InputButton, "save"))
+	if got, err := form.GetString("button"); err != nil || got != "save" {
+		t.Errorf("Expected %q for button but got %q (err: %v)", "save", got, err)
+	}
+	form.Validate("button").Required()
+	if form.HasErrors() {
+		t.Errorf("Expected no errors for button but got %v", form.Errors)
+	}
+	target := struct {
+		Button string
+	}{}
+	if err := form.Bind(&target); err != nil {
+		t.Fatalf("Unexpected error in Bind: %s", err)
+	}
+	if target.Button != "save" {
+		t.Errorf("Expected Button to be %q but got %q", "save", target.Button)
+	}
+	// Buttons are still left out of the submitted values.
+	if got := form.Values(); len(got) != 0 {
+		t.Errorf("Expected no values for the button but got %v", got)
+	}
+}
@@ -8,41 +8,106 @@ package form
 import (
 	"strconv"
 	"time"
-
-	"honnef.co/go/js/dom"
 )
 
-// Input is a go representation of an html input element.
+// Input is a go representation of an html input, select, textarea, or button
+// element.
 type Input struct {
-	// El is the original html input element for the Input.
-	El *dom.HTMLInputElement
+	// El is the Element that the Input was created from. When the Input was
+	// created by Parse, El is a *DOMElement which wraps the original html
+	// element. When it was created by ParseValues or ParseRequest, El only
+	// knows about the submitted name and value.
+	El Element
 	// Name is equal to the value of the input's name attribute.
 	Name string
-	// RawValue is equal to the input's value attribute.
+	// RawValue is equal to the input's value attribute. For select elements,
+	// it is the value of the first selected option (or an empty string if no
+	// option is selected).
 	RawValue string
+	// SelectedValues holds the values of all the selected options for select
+	// elements, in document order. For all other elements it is nil.
+	SelectedValues []string
+	// Checked is true iff the input is a checkbox or radio and it is checked.
+	Checked bool
 	// Type is equal to the input's type attribute. Note that this is sometimes
 	// different than the type reported by type property of the HTMLInputElement
-	// in the DOM API.
+	// in the DOM API. For select and textarea elements, Type is equal to the type
+	// property reported by the DOM API, i.e. one of InputSelect,
+	// InputSelectMultiple, or InputTextArea.
 	Type InputType
 }
 
-// NewInput creates a new Input object from the given html input element.
-func NewInput(el *dom.HTMLInputElement) *Input {
-	// Attempt to determine the type by first getting the type attribute
-	// directly. This is more reliable as some browsers will always return
-	// "text" as the type if the type attribute is not recognized/supported.
-	// If the type attribute is missing, fallback to using what the browser
-	// thinks the type is (which is probably "text").
-	inputType := InputType(el.GetAttribute("type"))
-	if inputType == "" {
-		inputType = InputType(el.Type)
-	}
+// NewInput creates a new Input object from the given Element. The Input holds
+// a snapshot of the name, value(s), checked state, and type of el at the time
+// NewInput was called.
+func NewInput(el Element) *Input {
 	return &Input{
-		El:       el,
-		Name:     el.Name,
-		RawValue: el.Value,
-		Type:     inputType,
+		El:             el,
+		Name:           el.Name(),
+		RawValue:       el.Value(),
+		SelectedValues: el.SelectedValues(),
+		Checked:        el.Checked(),
+		Type:           el.Type(),
+	}
+}
+
+// Values returns the values that the browser would submit for the input. For
+// checkboxes and radio buttons, it returns a slice containing only RawValue if
+// the input is checked and nil otherwise. For select elements, it returns all
+// the selected values. For buttons (including inputs with the type submit,
+// reset, button, or image), it returns nil, since the value of a button is only
+// submitted when it is used to submit the form. For all other inputs, it
+// returns a slice containing only RawValue.
+func (input Input) Values() []string {
+	switch input.Type {
+	case InputCheckbox, InputRadio:
+		if input.Checked {
+			return []string{input.RawValue}
+		}
+		return nil
+	case InputSelect, InputSelectMultiple:
+		return input.SelectedValues
+	default:
+		if isButton(input.Type) {
+			return nil
+		}
+		return []string{input.RawValue}
+	}
+}
+
+// isButton returns true iff typ is the type of a button, including inputs with
+// the type submit, reset, button, or image.
+func isButton(typ InputType) bool {
+	switch typ {
+	case InputButton, InputSubmit, InputReset, InputImage:
+		return true
+	}
+	return false
+}
+
+// isSubmitted returns true iff the browser would submit at least one value for
+// the input.
+func (input Input) isSubmitted() bool {
+	return len(input.Values()) > 0
+}
+
+// isOmitted returns true iff the browser would not submit the input, e.g.
+// because it is a checkbox which is not checked. Unlike isSubmitted, it
+// returns false for buttons, since the value of a button is submitted when it
+// is used to submit the form, and reading that value is the usual way to find
+// out which button was used.
+func (input Input) isOmitted() bool {
+	return !isButton(input.Type) && !input.isSubmitted()
+}
+
+// value returns RawValue unless the input is omitted (see isOmitted), in which
+// case it returns an empty string. The getters, validations, and Bind use it
+// so that an input which would not be submitted is treated like an empty one.
+func (input Input) value() string {
+	if input.isOmitted() {
+		return ""
 	}
+	return input.RawValue
 }
 
 // Int converts the value of the input to an int. It returns an error if the
@@ -70,33 +135,110 @@ func (input Input) Float() (float64, error) {
 // Bool converts the value of the input to a bool. For inputs with the type
 // checkbox or radio, Bool will return true iff the input has the checked
 // attribute. For all other input types it will attempt to parse the input value
-// as a bool. It returns an error if the value could not be converted.
+// as a bool. If the type is InputDefault, e.g. because the input was created by
+// ParseValues, the value "on" is also accepted as true, since it is what
+// browsers submit for a checked checkbox without a value attribute. It returns
+// an error if the value could not be converted.
 func (input Input) Bool() (bool, error) {
 	switch input.Type {
 	case InputCheckbox, InputRadio:
-		return input.El.Checked, nil
+		return input.Checked, nil
+	case InputDefault:
+		if input.RawValue == "on" {
+			return true, nil
+		}
+		return strconv.ParseBool(input.RawValue)
 	default:
 		return strconv.ParseBool(input.RawValue)
 	}
 }
 
-const (
-	rfc3339DateLayout          = "2006-01-02"
-	rfc3339DatetimeLocalLayout = "2006-01-02T15:04:05.999999999"
-)
+// UpdateElement copies the RawValue, Checked, and SelectedValues of the input
+// back to El, if El implements WritableElement. It is typically used after
+// changing the input, e.g. via Form.Fill. If El does not implement
+// WritableElement, UpdateElement does nothing.
+func (input *Input) UpdateElement() {
+	el, ok := input.El.(WritableElement)
+	if !ok {
+		return
+	}
+	switch input.Type {
+	case InputCheckbox, InputRadio:
+		el.SetChecked(input.Checked)
+	case InputSelect, InputSelectMultiple:
+		el.SetSelectedValues(input.SelectedValues)
+	default:
+		el.SetValue(input.RawValue)
+	}
+}
 
-// Time converts the value of the input to a time.Time. Time supports the time,
-// date, and datetime input types and assumes the input value adheres to the
-// rfc3339 standard (the default for form inputs). If the type of the input is
-// anything else, it will attempt to parse it as an rfc3339 datetime. It returns
-// an error if the value could not be converted.
+// Time converts the value of the input to a time.Time. Time supports the date,
+// datetime-local, month, week, and time input types and assumes the input value
+// is formatted the way the browser formats it for each type (see the html
+// spec). The seconds are optional for datetime-local and time inputs. Date,
+// month, and week inputs are converted to midnight UTC on the first day of the
+// date, month, or week respectively. Datetime-local inputs are converted to a
+// time in UTC with the same wall clock time, and time inputs are converted to a
+// time on January 1 of year 0 in UTC. If the type is InputDefault, e.g.
+// because the input was created by ParseValues, the value may be an rfc3339
+// datetime or formatted like the value of any of those input types. If the
+// type of the input is anything else, it will attempt to parse it as an rfc3339
+// datetime. It returns an error if the value could not be converted.
 func (input Input) Time() (time.Time, error) {
 	switch input.Type {
 	case InputDate:
 		return time.Parse(rfc3339DateLayout, input.RawValue)
 	case InputDateTimeLocal:
+		// The seconds are optional.
+		if t, err := time.Parse(rfc3339DatetimeLocalMinutesLayout, input.RawValue); err == nil {
+			return t, nil
+		}
 		return time.Parse(rfc3339DatetimeLocalLayout, input.RawValue)
+	case InputMonth:
+		return time.Parse(rfc3339MonthLayout, input.RawValue)
+	case InputWeek:
+		week, err := input.Week()
+		if err != nil {
+			return time.Time{}, err
+		}
+		return week.Start(), nil
+	case InputTime:
+		clock, err := input.Clock()
+		if err != nil {
+			return time.Time{}, err
+		}
+		return clock.On(time.Date(0, time.January, 1, 0, 0, 0, 0, time.UTC)), nil
+	case InputDefault:
+		t, err := time.Parse(time.RFC3339, input.RawValue)
+		if err == nil {
+			return t, nil
+		}
+		// The original type is not known, so try the formats of each of the
+		// date and time input types.
+		for _, typ := range []InputType{InputDateTimeLocal, InputDate, InputMonth, InputWeek, InputTime} {
+			typed := input
+			typed.Type = typ
+			if t, typedErr := typed.Time(); typedErr == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, err
 	default:
 		return time.Parse(time.RFC3339, input.RawValue)
 	}
 }
+
+// Clock converts the value of the input to a Clock, i.e. a time of day. The
+// value must be formatted like the value of an input with the type time, e.g.
+// "15:04" or "15:04:05". It returns an error if the value could not be
+// converted.
+func (input Input) Clock() (Clock, error) {
+	return ParseClock(input.RawValue)
+}
+
+// Week converts the value of the input to a Week. The value must be formatted
+// like the value of an input with the type week, e.g. "2006-W05". It returns an
+// error if the value could not be converted.
+func (input Input) Week() (Week, error) {
+	return ParseWeek(input.RawValue)
+}
@@ -6,10 +6,10 @@
 package main
 
 import (
-	"strings"
 	"time"
 
 	"github.com/go-humble/form"
+	"github.com/gopherjs/gopherjs/js"
 	"github.com/rusco/qunit"
 	"honnef.co/go/js/dom"
 )
@@ -30,54 +30,6 @@ func reset() {
 	container.SetInnerHTML("")
 }
 
-// CustomBinder implements form.Binder.
-type CustomBinder struct {
-	Int    int
-	String string
-}
-
-// BindForm implements the BindForm method of form.Binder. Instead of binding
-// the fields directly, We'll add 1 to the Int field and prepend a "_" to the
-// string field.
-func (b *CustomBinder) BindForm(form *form.Form) error {
-	if _, found := form.Inputs["int"]; found {
-		valInt, err := form.GetInt("int")
-		if err != nil {
-			return err
-		}
-		b.Int = valInt + 1
-	}
-	if _, found := form.Inputs["string"]; found {
-		valString, err := form.GetString("string")
-		if err != nil {
-			return err
-		}
-		b.String = "_" + valString
-	}
-	return nil
-}
-
-// Name is a custom type which implements form.InputBinder.
-type Name struct {
-	First string
-	Last  string
-}
-
-// BindInput implements the BindInput method of form.InputBinder. We split the
-// input into a first name and last name and assign each field of Name manually.
-func (name *Name) BindInput(input *form.Input) error {
-	names := strings.Split(input.RawValue, " ")
-	name.First = names[0]
-	name.Last = names[1]
-	return nil
-}
-
-// CustomInputBinder is a struct which has a single field of type Name. When we
-// call form.Bind on it, we expect Name.BindInput to be invoked.
-type CustomInputBinder struct {
-	Name Name
-}
-
 func main() {
 	qunit.Test("GetString", func(assert qunit.QUnitAssert) {
 		defer reset()
@@ -114,6 +66,83 @@ func main() {
 		}
 	})
 
+	qunit.Test("ParseNonInputElements", func(assert qunit.QUnitAssert) {
+		defer reset()
+		// Create a form with select, textarea, and button elements.
+		container.SetInnerHTML(`<form>
+			<select name="select">
+				<option value="a">A</option>
+				<option value="b" selected>B</option>
+			</select>
+			<select name="multiple" multiple>
+				<option value="a" selected>A</option>
+				<option value="b">B</option>
+				<option value="c" selected>C</option>
+			</select>
+			<textarea name="textarea">Some longer text.</textarea>
+			<button name="button" value="save">Save</button>
+			<fieldset name="fieldset"></fieldset>
+			</form>`)
+		formEl := container.QuerySelector("form")
+		form, err := form.Parse(formEl)
+		assertNoError(assert, err, "")
+		expectedValues := map[string]string{
+			"select":   "b",
+			"multiple": "a",
+			"textarea": "Some longer text.",
+			"button":   "save",
+		}
+		// Check that the parsed value for each input is correct.
+		for name, expectedValue := range expectedValues {
+			got, err := form.GetString(name)
+			assertNoError(assert, err, "")
+			assert.Equal(got, expectedValue, "Incorrect value for field: "+name)
+		}
+		assert.DeepEqual(form.Inputs["multiple"].SelectedValues, []string{"a", "c"},
+			"Incorrect selected values for field: multiple")
+		assert.Equal(form.Inputs["multiple"].Type, "select-multiple",
+			"Incorrect type for field: multiple")
+		assert.Equal(form.Inputs["button"].Type, "submit",
+			"Incorrect type for field: button")
+		_, found := form.Inputs["fieldset"]
+		assert.Equal(found, false, "Expected fieldset to be skipped")
+	})
+
+	qunit.Test("MultipleInputsWithSameName", func(assert qunit.QUnitAssert) {
+		defer reset()
+		// Create a form with a radio group, a checkbox group, and some repeated
+		// text inputs.
+		container.SetInnerHTML(`<form>
+			<input type="radio" name="color" value="red" >
+			<input type="radio" name="color" value="green" checked >
+			<input type="radio" name="color" value="blue" >
+			<input type="checkbox" name="ids" value="1" checked >
+			<input type="checkbox" name="ids" value="2" >
+			<input type="checkbox" name="ids" value="3" checked >
+			<input name="tags[]" value="foo" >
+			<input name="tags[]" value="bar" >
+			</form>`)
+		formEl := container.QuerySelector("form")
+		form, err := form.Parse(formEl)
+		assertNoError(assert, err, "")
+		// Check that the checked radio button is the one in form.Inputs.
+		color, err := form.GetString("color")
+		assertNoError(assert, err, "")
+		assert.Equal(color, "green", "Incorrect value for field: color")
+		assert.Equal(len(form.Groups["color"]), 3, "Expected 3 inputs for field: color")
+		// Check that only the checked checkboxes are included.
+		ids, err := form.GetInts("ids")
+		assertNoError(assert, err, "")
+		assert.DeepEqual(ids, []int{1, 3}, "Incorrect values for field: ids")
+		// Check that all repeated inputs are included.
+		tags, err := form.GetStrings("tags[]")
+		assertNoError(assert, err, "")
+		assert.DeepEqual(tags, []string{"foo", "bar"}, "Incorrect values for field: tags[]")
+		// Check that a non-existing input results in an error.
+		_, err = form.GetStrings("non-existing")
+		assert.Equal(err != nil, true, "Expected an error for non-existing input")
+	})
+
 	qunit.Test("GetInt", func(assert qunit.QUnitAssert) {
 		defer reset()
 		// Create a form with some inputs and values. All the input types here
@@ -251,547 +280,281 @@ func main() {
 		}
 	})
 
-	qunit.Test("ValidateRequired", func(assert qunit.QUnitAssert) {
-		defer reset()
-		// Create a form with some inputs and values.
-		container.SetInnerHTML(`<form>
-			<input name="non-empty" value="foo" >
-			<input name="empty" value="" >
-			</form>`)
-		formEl := container.QuerySelector("form")
-		form, err := form.Parse(formEl)
-		assertNoError(assert, err, "")
-		// Check that a non-empty input does not add a validation error.
-		form.Validate("non-empty").Required()
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that an empty input does add a validation error with a custom
-		// message.
-		customMessage := "empty cannot be blank."
-		form.Validate("empty").Requiredf(customMessage)
-		assert.Equal(len(form.Errors), 1,
-			"Expected form to have 1 error because 'empty' is required.")
-		assert.Equal(form.Errors[0].Error(), customMessage,
-			"Custom message was not set with Requiredf")
-		// Check that a non-existing input does add a validation error.
-		form.Validate("non-existing").Required()
-		assert.Equal(len(form.Errors), 2,
-			"Expected form to have 2 errorss because 'non-existing' and 'empty' are required.")
-	})
-
-	qunit.Test("ValidateLess", func(assert qunit.QUnitAssert) {
-		defer reset()
-		// Create a form with some inputs and values.
-		container.SetInnerHTML(`<form>
-			<input name="valid" value="5" >
-			<input name="invalid" value="10" >
-			<input name="non-integer" value="foo" >
-			</form>`)
-		formEl := container.QuerySelector("form")
-		form, err := form.Parse(formEl)
-		assertNoError(assert, err, "")
-		// Check that a valid input does not add any validation errors.
-		form.Validate("valid").Less(10)
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that a non-existing input does not add any validation errors.
-		form.Validate("non-existing").Less(10)
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that an invalid input does add a validation error with a custom
-		// message.
-		customMessage := "invalid input was invalid becase it was not less than 10."
-		form.Validate("invalid").Lessf(10, customMessage)
-		assert.Equal(len(form.Errors), 1,
-			"Expected form to have 1 error because 'invalid' is not less than 10.")
-		assert.Equal(form.Errors[0].Error(), customMessage,
-			"Custom message was not set with Lessf")
-		// Check that a input which is not an integer adds the correct error
-		// message.
-		form.Validate("non-integer").Less(10)
-		assert.Equal(len(form.Errors), 2,
-			"Expected form to have 2 errors because 'non-integer' is not an integer.")
-		assert.Equal(form.Errors[1].Error(), "non-integer must be an integer.",
-			"Error was not added when input was a non-integer.")
-	})
-
-	qunit.Test("ValidateLessOrEqual", func(assert qunit.QUnitAssert) {
-		defer reset()
-		// Create a form with some inputs and values.
-		container.SetInnerHTML(`<form>
-			<input name="valid" value="5" >
-			<input name="invalid" value="11" >
-			<input name="non-integer" value="foo" >
-			</form>`)
-		formEl := container.QuerySelector("form")
-		form, err := form.Parse(formEl)
-		assertNoError(assert, err, "")
-		// Check that a valid input does not add any validation errors.
-		form.Validate("valid").LessOrEqual(10)
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that a non-existing input does not add any validation errors.
-		form.Validate("non-existing").LessOrEqual(10)
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that an invalid input does add a validation error with a custom
-		// message.
-		customMessage := "invalid input was invalid becase it was not less than or equal to 10."
-		form.Validate("invalid").LessOrEqualf(10, customMessage)
-		assert.Equal(len(form.Errors), 1,
-			"Expected form to have 1 error because 'invalid' is not less than or equal to 10.")
-		assert.Equal(form.Errors[0].Error(), customMessage,
-			"Custom message was not set with LessOrEqualf")
-		// Check that a input which is not an integer adds the correct error
-		// message.
-		form.Validate("non-integer").LessOrEqual(10)
-		assert.Equal(len(form.Errors), 2,
-			"Expected form to have 2 errors because 'non-integer' is not an integer.")
-		assert.Equal(form.Errors[1].Error(), "non-integer must be an integer.",
-			"Error was not added when input was a non-integer.")
-	})
-
-	qunit.Test("ValidateGreater", func(assert qunit.QUnitAssert) {
-		defer reset()
-		// Create a form with some inputs and values.
-		container.SetInnerHTML(`<form>
-			<input name="valid" value="15" >
-			<input name="invalid" value="10" >
-			<input name="non-integer" value="foo" >
-			</form>`)
-		formEl := container.QuerySelector("form")
-		form, err := form.Parse(formEl)
-		assertNoError(assert, err, "")
-		// Check that a valid input does not add any validation errors.
-		form.Validate("valid").Greater(10)
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that a non-existing input does not add any validation errors.
-		form.Validate("non-existing").Greater(10)
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that an invalid input does add a validation error with a custom
-		// message.
-		customMessage := "invalid input was invalid becase it was not greater than 10."
-		form.Validate("invalid").Greaterf(10, customMessage)
-		assert.Equal(len(form.Errors), 1,
-			"Expected form to have 1 error because 'invalid' is not greater than 10.")
-		assert.Equal(form.Errors[0].Error(), customMessage,
-			"Custom message was not set with Greaterf")
-		// Check that a input which is not an integer adds the correct error
-		// message.
-		form.Validate("non-integer").Greater(10)
-		assert.Equal(len(form.Errors), 2,
-			"Expected form to have 2 errors because 'non-integer' is not an integer.")
-		assert.Equal(form.Errors[1].Error(), "non-integer must be an integer.",
-			"Error was not added when input was a non-integer.")
-	})
-
-	qunit.Test("ValidateGreaterOrEqual", func(assert qunit.QUnitAssert) {
-		defer reset()
-		// Create a form with some inputs and values.
-		container.SetInnerHTML(`<form>
-			<input name="valid" value="15" >
-			<input name="invalid" value="9" >
-			<input name="non-integer" value="foo" >
-			</form>`)
-		formEl := container.QuerySelector("form")
-		form, err := form.Parse(formEl)
-		assertNoError(assert, err, "")
-		// Check that a valid input does not add any validation errors.
-		form.Validate("valid").GreaterOrEqual(10)
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that a non-existing input does not add any validation errors.
-		form.Validate("non-existing").GreaterOrEqual(10)
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that an invalid input does add a validation error with a custom
-		// message.
-		customMessage := "invalid input was invalid becase it was not greater than or equal to 10."
-		form.Validate("invalid").GreaterOrEqualf(10, customMessage)
-		assert.Equal(len(form.Errors), 1,
-			"Expected form to have 1 error because 'invalid' is not greater than or equal to 10.")
-		assert.Equal(form.Errors[0].Error(), customMessage,
-			"Custom message was not set with GreaterOrEqualf")
-		// Check that a input which is not an integer adds the correct error
-		// message.
-		form.Validate("non-integer").GreaterOrEqual(10)
-		assert.Equal(len(form.Errors), 2,
-			"Expected form to have 2 errors because 'non-integer' is not an integer.")
-		assert.Equal(form.Errors[1].Error(), "non-integer must be an integer.",
-			"Error was not added when input was a non-integer.")
-	})
-
-	qunit.Test("ValidateIsInt", func(assert qunit.QUnitAssert) {
-		defer reset()
-		// Create a form with some inputs and values.
-		container.SetInnerHTML(`<form>
-			<input name="valid" value="5" >
-			<input name="invalid" value="foo" >
-			</form>`)
-		formEl := container.QuerySelector("form")
-		form, err := form.Parse(formEl)
-		assertNoError(assert, err, "")
-		// Check that a valid input does not add any validation errors.
-		form.Validate("valid").IsInt()
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that a non-existing input does not add any validation errors.
-		form.Validate("non-existing").IsInt()
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that an invalid input does add a validation error with a custom
-		// message.
-		customMessage := "invalid input was invalid becase it not an integer."
-		form.Validate("invalid").IsIntf(customMessage)
-		assert.Equal(len(form.Errors), 1,
-			"Expected form to have 1 error because 'invalid' is not an integer.")
-		assert.Equal(form.Errors[0].Error(), customMessage,
-			"Custom message was not set with IsIntf")
-	})
-
-	qunit.Test("ValidateLessFloat", func(assert qunit.QUnitAssert) {
+	qunit.Test("Fill", func(assert qunit.QUnitAssert) {
 		defer reset()
-		// Create a form with some inputs and values.
+		// Create a form with some empty inputs.
 		container.SetInnerHTML(`<form>
-			<input name="valid" value="5.4" >
-			<input name="invalid" value="10.0" >
-			<input name="non-float" value="foo" >
+			<input name="name" >
+			<input type="checkbox" name="admin" >
+			<select name="size">
+				<option value="small">Small</option>
+				<option value="large">Large</option>
+			</select>
 			</form>`)
 		formEl := container.QuerySelector("form")
-		form, err := form.Parse(formEl)
+		f, err := form.Parse(formEl)
 		assertNoError(assert, err, "")
-		// Check that a valid input does not add any validation errors.
-		form.Validate("valid").LessFloat(10.0)
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that a non-existing input does not add any validation errors.
-		form.Validate("non-existing").LessFloat(10.0)
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that an invalid input does add a validation error with a custom
-		// message.
-		customMessage := "invalid input was invalid becase it was not less than 10.0."
-		form.Validate("invalid").LessFloatf(10.0, customMessage)
-		assert.Equal(len(form.Errors), 1,
-			"Expected form to have 1 error because 'invalid' is not less than 10.0.")
-		assert.Equal(form.Errors[0].Error(), customMessage,
-			"Custom message was not set with LessFloatf")
-		// Check that a input which is not a float adds the correct error
-		// message.
-		form.Validate("non-float").LessFloat(10.0)
-		assert.Equal(len(form.Errors), 2,
-			"Expected form to have 2 errors because 'non-float' is not a number.")
-		assert.Equal(form.Errors[1].Error(), "non-float must be a number.",
-			"Error was not added when input was a non-float.")
-	})
-
-	qunit.Test("ValidateLessOrEqualFloat", func(assert qunit.QUnitAssert) {
-		defer reset()
-		// Create a form with some inputs and values.
-		container.SetInnerHTML(`<form>
-			<input name="valid" value="5.4" >
-			<input name="invalid" value="10.1" >
-			<input name="non-float" value="foo" >
-			</form>`)
-		formEl := container.QuerySelector("form")
-		form, err := form.Parse(formEl)
-		assertNoError(assert, err, "")
-		// Check that a valid input does not add any validation errors.
-		form.Validate("valid").LessOrEqualFloat(10.0)
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that a non-existing input does not add any validation errors.
-		form.Validate("non-existing").LessOrEqualFloat(10.0)
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that an invalid input does add a validation error with a custom
-		// message.
-		customMessage := "invalid input was invalid becase it was not less than or equal to 10.0."
-		form.Validate("invalid").LessOrEqualFloatf(10.0, customMessage)
-		assert.Equal(len(form.Errors), 1,
-			"Expected form to have 1 error because 'invalid' is not less than or equal to 10.0.")
-		assert.Equal(form.Errors[0].Error(), customMessage,
-			"Custom message was not set with LessOrEqualFloatf")
-		// Check that a input which is not a float adds the correct error
-		// message.
-		form.Validate("non-float").LessOrEqualFloat(10.0)
-		assert.Equal(len(form.Errors), 2,
-			"Expected form to have 2 errors because 'non-float' is not a number.")
-		assert.Equal(form.Errors[1].Error(), "non-float must be a number.",
-			"Error was not added when input was a non-float.")
-	})
-
-	qunit.Test("ValidateGreaterFloat", func(assert qunit.QUnitAssert) {
-		defer reset()
-		// Create a form with some inputs and values.
-		container.SetInnerHTML(`<form>
-			<input name="valid" value="15.7" >
-			<input name="invalid" value="10.0" >
-			<input name="non-float" value="foo" >
-			</form>`)
-		formEl := container.QuerySelector("form")
-		form, err := form.Parse(formEl)
+		source := struct {
+			Name  string
+			Admin bool
+			Size  string
+		}{"Foo", true, "large"}
+		assertNoError(assert, f.Fill(source), "")
+		// Re-parse the form to check that the DOM elements were updated.
+		f, err = form.Parse(formEl)
 		assertNoError(assert, err, "")
-		// Check that a valid input does not add any validation errors.
-		form.Validate("valid").GreaterFloat(10.0)
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that a non-existing input does not add any validation errors.
-		form.Validate("non-existing").GreaterFloat(10.0)
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that an invalid input does add a validation error with a custom
-		// message.
-		customMessage := "invalid input was invalid becase it was not greater than 10.0."
-		form.Validate("invalid").GreaterFloatf(10.0, customMessage)
-		assert.Equal(len(form.Errors), 1,
-			"Expected form to have 1 error because 'invalid' is not greater than 10.0.")
-		assert.Equal(form.Errors[0].Error(), customMessage,
-			"Custom message was not set with GreaterFloatf")
-		// Check that a input which is not a float adds the correct error
-		// message.
-		form.Validate("non-float").GreaterFloat(10.0)
-		assert.Equal(len(form.Errors), 2,
-			"Expected form to have 2 errors because 'non-float' is not a number.")
-		assert.Equal(form.Errors[1].Error(), "non-float must be a number.",
-			"Error was not added when input was a non-float.")
+		name, _ := f.GetString("name")
+		assert.Equal(name, "Foo", "Incorrect value for field: name")
+		admin, _ := f.GetBool("admin")
+		assert.Equal(admin, true, "Incorrect value for field: admin")
+		size, _ := f.GetString("size")
+		assert.Equal(size, "large", "Incorrect value for field: size")
 	})
 
-	qunit.Test("ValidateGreaterOrEqualFloat", func(assert qunit.QUnitAssert) {
+	qunit.Test("BindLive", func(assert qunit.QUnitAssert) {
 		defer reset()
-		// Create a form with some inputs and values.
 		container.SetInnerHTML(`<form>
-			<input name="valid" value="15.7" >
-			<input name="invalid" value="9.9" >
-			<input name="non-float" value="foo" >
+			<input name="name" value="Foo" >
 			</form>`)
 		formEl := container.QuerySelector("form")
-		form, err := form.Parse(formEl)
+		inputEl := container.QuerySelector("input").(*dom.HTMLInputElement)
+		target := struct {
+			Name string
+		}{}
+		validations := 0
+		binding, err := form.BindLive(formEl, &target, func(f *form.Form) {
+			validations++
+			f.Validate("name").Required()
+		})
 		assertNoError(assert, err, "")
-		// Check that a valid input does not add any validation errors.
-		form.Validate("valid").GreaterOrEqualFloat(10.0)
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that a non-existing input does not add any validation errors.
-		form.Validate("non-existing").GreaterOrEqualFloat(10.0)
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that an invalid input does add a validation error with a custom
-		// message.
-		customMessage := "invalid input was invalid becase it was not greater than or equal to 10.0."
-		form.Validate("invalid").GreaterOrEqualFloatf(10.0, customMessage)
-		assert.Equal(len(form.Errors), 1,
-			"Expected form to have 1 error because 'invalid' is not greater than or equal to 10.0.")
-		assert.Equal(form.Errors[0].Error(), customMessage,
-			"Custom message was not set with GreaterOrEqualFloatf")
-		// Check that a input which is not a float adds the correct error
-		// message.
-		form.Validate("non-float").GreaterOrEqualFloat(10.0)
-		assert.Equal(len(form.Errors), 2,
-			"Expected form to have 2 errors because 'non-float' is not a number.")
-		assert.Equal(form.Errors[1].Error(), "non-float must be a number.",
-			"Error was not added when input was a non-float.")
+		assert.Equal(target.Name, "Foo", "target.Name was not bound initially.")
+		// Change the input value and dispatch an input event.
+		inputEl.Value = ""
+		dispatchEvent(inputEl, "input")
+		assert.Equal(target.Name, "", "target.Name was not updated after input event.")
+		assert.Equal(binding.Form.HasErrors(), true, "Expected validation error after input event.")
+		assert.Equal(validations, 2, "Expected validate to run on every change.")
+		// Change the struct and push the change to the DOM.
+		target.Name = "Bar"
+		assertNoError(assert, binding.Update(), "")
+		assert.Equal(inputEl.Value, "Bar", "Input value was not updated by Update.")
+		// After Close, events should no longer affect the struct.
+		binding.Close()
+		inputEl.Value = "Baz"
+		dispatchEvent(inputEl, "input")
+		assert.Equal(target.Name, "Bar", "target.Name was changed after Close.")
 	})
 
-	qunit.Test("ValidateIsFloat", func(assert qunit.QUnitAssert) {
+	qunit.Test("BindLive with BindErrors", func(assert qunit.QUnitAssert) {
 		defer reset()
-		// Create a form with some inputs and values.
 		container.SetInnerHTML(`<form>
-			<input name="valid" value="5.3" >
-			<input name="invalid" value="foo" >
+			<input name="name" value="Foo" >
+			<input type="number" name="age" value="" >
 			</form>`)
 		formEl := container.QuerySelector("form")
-		form, err := form.Parse(formEl)
+		inputEl := container.QuerySelector("input[name=age]").(*dom.HTMLInputElement)
+		target := struct {
+			Name string
+			Age  int
+		}{}
+		binding, err := form.BindLive(formEl, &target, nil)
 		assertNoError(assert, err, "")
-		// Check that a valid input does not add any validation errors.
-		form.Validate("valid").IsFloat()
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that a non-existing input does not add any validation errors.
-		form.Validate("non-existing").IsFloat()
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that an invalid input does add a validation error with a custom
-		// message.
-		customMessage := "invalid input was invalid becase it not a float."
-		form.Validate("invalid").IsFloatf(customMessage)
-		assert.Equal(len(form.Errors), 1,
-			"Expected form to have 1 error because 'invalid' is not a float.")
-		assert.Equal(form.Errors[0].Error(), customMessage,
-			"Custom message was not set with IsFloatf")
+		if binding == nil {
+			return
+		}
+		assert.Equal(target.Name, "Foo", "target.Name was not bound initially.")
+		_, isBindErrs := binding.Err.(form.BindErrors)
+		assert.Equal(isBindErrs, true, "Expected binding.Err to hold the BindErrors.")
+		// The listeners should be attached despite the error.
+		inputEl.Value = "42"
+		dispatchEvent(inputEl, "input")
+		assert.Equal(target.Age, 42, "target.Age was not updated after input event.")
+		assertNoError(assert, binding.Err, "")
+		binding.Close()
 	})
 
-	qunit.Test("ValidateIsBool", func(assert qunit.QUnitAssert) {
+	qunit.Test("Label", func(assert qunit.QUnitAssert) {
 		defer reset()
-		// Create a form with some inputs and values.
 		container.SetInnerHTML(`<form>
-			<input name="valid" value="true" >
-			<input type="checkbox" name="checkbox" checked >
-			<input name="invalid" value="foo" >
+			<label for="email-input">Email address:</label>
+			<input id="email-input" name="email" >
+			<label>Size <select name="size"><option value="s">Small</option></select></label>
+			<input name="age" data-label="Your age" >
+			<input name="plain" >
 			</form>`)
 		formEl := container.QuerySelector("form")
-		form, err := form.Parse(formEl)
+		f, err := form.Parse(formEl)
 		assertNoError(assert, err, "")
-		// Check that a valid input does not add any validation errors.
-		form.Validate("valid").IsBool()
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that the checkbox input does not add any validation errors.
-		form.Validate("checkbox").IsBool()
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that a non-existing input does not add any validation errors.
-		form.Validate("non-existing").IsBool()
-		assert.Equal(form.HasErrors(), false, "Expected form to have no errors")
-		// Check that an invalid input does add a validation error with a custom
-		// message.
-		customMessage := "invalid input was invalid becase it not a boolean."
-		form.Validate("invalid").IsBoolf(customMessage)
-		assert.Equal(len(form.Errors), 1,
-			"Expected form to have 1 error because 'invalid' is not a boolean.")
-		assert.Equal(form.Errors[0].Error(), customMessage,
-			"Custom message was not set with IsBoolf")
+		expectedLabels := map[string]string{
+			"email": "Email address",
+			"size":  "Size",
+			"age":   "Your age",
+			"plain": "plain",
+		}
+		for name, expected := range expectedLabels {
+			assert.Equal(f.Label(name), expected, "Incorrect label for field: "+name)
+		}
+		f.Validate("plain").Required()
+		f.Validate("email").Required()
+		assert.Equal(f.Errors[1].Error(), "Email address is required.", "Expected the message to use the label.")
 	})
 
-	qunit.Test("Bind", func(assert qunit.QUnitAssert) {
+	qunit.Test("ErrorRenderer", func(assert qunit.QUnitAssert) {
 		defer reset()
-		// Create a form with some inputs and values.
 		container.SetInnerHTML(`<form>
-			<input name="string" value="foo" >
-			<input name="bytes" value="bar" >
-			<input type="number" name="int" value="4" >
-			<input type="number" name="int8" value="8" >
-			<input type="number" name="int16" value="15" >
-			<input type="number" name="int32" value="16" >
-			<input type="number" name="int64" value="23" >
-			<input type="number" name="uint" value="42" >
-			<input type="number" name="uint8" value="1" >
-			<input type="number" name="uint16" value="2" >
-			<input type="number" name="uint32" value="3" >
-			<input type="number" name="uint64" value="4" >
-			<input type="number" name="float32" value="39.7" >
-			<input type="number" name="float64" value="12.6" >
-			<input type="checkbox" name="bool" checked >
-			<input type="datetime" name="time" value="1985-12-03T23:59:34-08:00" >
-			</form>`)
+			<input name="name" >
+			<input name="age" value="foo" >
+			<span data-error-for="age"></span>
+			<label><input type="radio" name="size" value="s" > Small</label>
+			<label><input type="radio" name="size" value="m" > Medium</label>
+			<input name="email" value="foo@example.com" >
+			</form>
+			<div id="summary"></div>`)
 		formEl := container.QuerySelector("form")
-		form, err := form.Parse(formEl)
-		assertNoError(assert, err, "")
-		// Bind the form to some target and check the results.
-		target := struct {
-			String  string
-			Bytes   []byte
-			Int     int
-			Int8    int8
-			Int16   int16
-			Int32   int32
-			Int64   int64
-			Uint    uint
-			Uint8   uint8
-			Uint16  uint16
-			Uint32  uint32
-			Uint64  uint64
-			Float32 float32
-			Float64 float64
-			Bool    bool
-			Time    time.Time
-		}{}
-		err = form.Bind(&target)
+		nameEl := container.QuerySelector("[name=name]")
+		emailEl := container.QuerySelector("[name=email]")
+		f, err := form.Parse(formEl)
 		assertNoError(assert, err, "")
-		assert.Equal(target.String, "foo", "target.String was not correct.")
-		assert.DeepEqual(target.Bytes, []byte("bar"), "target.Bytes was not correct.")
-		assert.Equal(target.Int, 4, "target.Int was not correct.")
-		assert.Equal(target.Int8, 8, "target.Int8 was not correct.")
-		assert.Equal(target.Int16, 15, "target.Int16 was not correct.")
-		assert.Equal(target.Int32, 16, "target.Int32 was not correct.")
-		assert.Equal(target.Int64, 23, "target.Int64 was not correct.")
-		assert.Equal(target.Uint, 42, "target.Uint was not correct.")
-		assert.Equal(target.Uint8, 1, "target.Uint8 was not correct.")
-		assert.Equal(target.Uint16, 2, "target.Uint16 was not correct.")
-		assert.Equal(target.Uint32, 3, "target.Uint32 was not correct.")
-		assert.Equal(target.Uint64, 4, "target.Uint64 was not correct.")
-		assert.Equal(target.Bool, true, "target.Bool was not correct.")
-		assert.DeepEqual(target.Time,
-			mustParseTime(time.RFC3339, "1985-12-03T23:59:34-08:00"),
-			"target.Time was not correct.")
+		f.Validate("name").Required()
+		f.Validate("age").IsInt()
+		f.Validate("size").Required()
+		f.Validate("email").IsEmail()
+		renderer := form.NewErrorRenderer()
+		renderer.Summary = container.QuerySelector("#summary")
+		renderer.Render(f)
+		assert.Equal(nameEl.Class().Contains("invalid"), true, "Expected name to have the invalid class.")
+		assert.Equal(nameEl.GetAttribute("aria-invalid"), "true", "Expected name to have aria-invalid.")
+		assert.Equal(emailEl.Class().Contains("valid"), true, "Expected email to have the valid class.")
+		assert.Equal(emailEl.HasAttribute("aria-invalid"), false, "Expected email not to have aria-invalid.")
+		nameMsg := nameEl.NextElementSibling()
+		assert.Equal(nameMsg.GetAttribute("data-form-error"), "name", "Expected a message after name.")
+		assert.Equal(nameMsg.TextContent(), "name is required.", "Incorrect message for name.")
+		ageContainer := container.QuerySelector("[data-error-for=age]")
+		assert.Equal(ageContainer.TextContent(), "age must be an integer.", "Expected the message for age in its container.")
+		sizeMsg := container.QuerySelectorAll("label")[1].NextElementSibling()
+		assert.Equal(sizeMsg.GetAttribute("data-form-error"), "size", "Expected a message after the last radio label.")
+		assert.Equal(len(container.QuerySelectorAll("#summary li")), 3, "Expected 3 errors in the summary.")
+		// Rendering again must remove the stale errors.
+		f.ClearErrors()
+		f.Validate("age").IsInt()
+		renderer.Render(f)
+		assert.Equal(nameEl.Class().Contains("invalid"), false, "Expected name not to have the invalid class.")
+		assert.Equal(nameEl.Class().Contains("valid"), true, "Expected name to have the valid class.")
+		assert.Equal(nameEl.HasAttribute("aria-invalid"), false, "Expected aria-invalid to be removed from name.")
+		assert.Equal(len(container.QuerySelectorAll("[data-form-error]")), 1, "Expected only the message for age.")
+		assert.Equal(len(container.QuerySelectorAll("#summary li")), 1, "Expected 1 error in the summary.")
+		renderer.Clear(f)
+		assert.Equal(len(container.QuerySelectorAll("[data-form-error]")), 0, "Expected no messages after Clear.")
+		assert.Equal(emailEl.Class().Contains("valid"), false, "Expected the valid class to be removed by Clear.")
 	})
 
-	qunit.Test("BindWithPointers", func(assert qunit.QUnitAssert) {
+	qunit.Test("ConstraintValidation", func(assert qunit.QUnitAssert) {
 		defer reset()
-		// Create a form with some inputs and values.
 		container.SetInnerHTML(`<form>
-			<input name="string" value="foo" >
-			<input name="bytes" value="bar" >
-			<input type="number" name="int" value="4" >
-			<input type="number" name="int8" value="8" >
-			<input type="number" name="int16" value="15" >
-			<input type="number" name="int32" value="16" >
-			<input type="number" name="int64" value="23" >
-			<input type="number" name="uint" value="42" >
-			<input type="number" name="uint8" value="1" >
-			<input type="number" name="uint16" value="2" >
-			<input type="number" name="uint32" value="3" >
-			<input type="number" name="uint64" value="4" >
-			<input type="number" name="float32" value="39.7" >
-			<input type="number" name="float64" value="12.6" >
-			<input type="checkbox" name="bool" checked >
-			<input type="datetime" name="time" value="1985-12-03T23:59:34-08:00" >
+			<input name="name" required >
+			<input type="email" name="email" value="foo" >
+			<input type="number" name="age" min="1" value="0" >
+			<input type="radio" name="size" value="s" required >
+			<input type="radio" name="size" value="m" required >
+			<input name="nickname" value="foo" >
 			</form>`)
 		formEl := container.QuerySelector("form")
-		form, err := form.Parse(formEl)
+		nicknameEl := container.QuerySelector("[name=nickname]")
+		f, err := form.Parse(formEl)
 		assertNoError(assert, err, "")
-		// Bind the form to some target and check the results.
-		target := struct {
-			String  *string
-			Bytes   *[]byte
-			Int     *int
-			Int8    *int8
-			Int16   *int16
-			Int32   *int32
-			Int64   *int64
-			Uint    *uint
-			Uint8   *uint8
-			Uint16  *uint16
-			Uint32  *uint32
-			Uint64  *uint64
-			Float32 *float32
-			Float64 *float64
-			Bool    *bool
-			Time    *time.Time
-		}{}
-		err = form.Bind(&target)
-		assertNoError(assert, err, "")
-		assert.Equal(*target.String, "foo", "target.String was not correct.")
-		assert.DeepEqual(*target.Bytes, []byte("bar"), "target.Bytes was not correct.")
-		assert.Equal(*target.Int, 4, "target.Int was not correct.")
-		assert.Equal(*target.Int8, 8, "target.Int8 was not correct.")
-		assert.Equal(*target.Int16, 15, "target.Int16 was not correct.")
-		assert.Equal(*target.Int32, 16, "target.Int32 was not correct.")
-		assert.Equal(*target.Int64, 23, "target.Int64 was not correct.")
-		assert.Equal(*target.Uint, 42, "target.Uint was not correct.")
-		assert.Equal(*target.Uint8, 1, "target.Uint8 was not correct.")
-		assert.Equal(*target.Uint16, 2, "target.Uint16 was not correct.")
-		assert.Equal(*target.Uint32, 3, "target.Uint32 was not correct.")
-		assert.Equal(*target.Uint64, 4, "target.Uint64 was not correct.")
-		assert.Equal(*target.Bool, true, "target.Bool was not correct.")
-		assert.DeepEqual(*target.Time,
-			mustParseTime(time.RFC3339, "1985-12-03T23:59:34-08:00"),
-			"target.Time was not correct.")
+		f.ImportValidity()
+		codes := map[string]string{}
+		for _, err := range f.Errors {
+			valErr := err.(*form.ValidationError)
+			codes[valErr.InputName] = valErr.Code
+		}
+		assert.Equal(len(f.Errors), 4, "Expected 4 imported errors.")
+		assert.Equal(codes["name"], form.RuleRequired, "Incorrect code for name.")
+		assert.Equal(codes["email"], form.RuleEmail, "Incorrect code for email.")
+		assert.Equal(codes["age"], form.RuleMin, "Incorrect code for age.")
+		assert.Equal(codes["size"], form.RuleRequired, "Incorrect code for size.")
+		// Push a custom error to the browser.
+		f.ClearErrors()
+		f.Validate("nickname").MinLength(5)
+		f.SetCustomValidity()
+		assert.Equal(nicknameEl.Underlying().Get("validationMessage").String(), "nickname must be at least 5 characters long.", "Expected the custom validity message.")
+		assert.Equal(len(container.QuerySelectorAll(":invalid")) > 0, true, "Expected :invalid to match.")
+		assert.Equal(f.CheckValidity(), false, "Expected CheckValidity to return false.")
+		// Importing again must not pick up the custom error.
+		f.ClearErrors()
+		f.ImportValidity()
+		assert.Equal(f.HasErrorsFor("nickname"), false, "Expected the custom error to be ignored by ImportValidity.")
+		assert.Equal(nicknameEl.Underlying().Get("validationMessage").String(), "nickname must be at least 5 characters long.", "Expected the custom validity message to be kept.")
+		// Clearing the errors clears the custom validity message.
+		f.ClearErrors()
+		f.SetCustomValidity()
+		assert.Equal(nicknameEl.Underlying().Get("validationMessage").String(), "", "Expected the custom validity message to be cleared.")
 	})
 
-	qunit.Test("Binder", func(assert qunit.QUnitAssert) {
+	qunit.Test("ValidateLive", func(assert qunit.QUnitAssert) {
 		defer reset()
-		// Create a form with some inputs and values.
 		container.SetInnerHTML(`<form>
-			<input name="string" value="foo" >
-			<input name="int" type="number" value="42" >
+			<input name="name" value="Foo" >
+			<input name="email" >
 			</form>`)
 		formEl := container.QuerySelector("form")
-		form, err := form.Parse(formEl)
-		assertNoError(assert, err, "")
-		// Bind the form to our CustomBinder type and check the results.
-		binder := &CustomBinder{}
-		err = form.Bind(binder)
+		nameEl := container.QuerySelector("[name=name]").(*dom.HTMLInputElement)
+		emailEl := container.QuerySelector("[name=email]").(*dom.HTMLInputElement)
+		validations := 0
+		lv, err := form.ValidateLive(formEl, func(f *form.Form) {
+			validations++
+			f.Validate("name").Required()
+			f.Validate("email").Required()
+		})
 		assertNoError(assert, err, "")
-		assert.Equal(binder.String, "_foo", "binder.String was not correct.")
-		assert.Equal(binder.Int, 43, "binder.Int was not correct.")
+		lv.Renderer = form.NewErrorRenderer()
+		assert.Equal(validations, 1, "Expected the form to be validated initially.")
+		assert.Equal(lv.Form.HasErrorsFor("email"), true, "Expected an error for email.")
+		assert.Equal(len(lv.TouchedErrors()), 0, "Expected no errors to be shown before any input is touched.")
+		// Input events are debounced.
+		nameEl.Value = ""
+		dispatchEvent(nameEl, "input")
+		assert.Equal(validations, 1, "Expected the input event to be debounced.")
+		assert.Equal(lv.Dirty("name"), false, "Expected name not to be dirty before validation.")
+		// A change event validates immediately and touches the input.
+		dispatchEvent(nameEl, "change")
+		assert.Equal(validations, 2, "Expected the change event to validate immediately.")
+		assert.Equal(lv.Touched("name"), true, "Expected name to be touched.")
+		assert.Equal(lv.Dirty("name"), true, "Expected name to be dirty.")
+		assert.Equal(lv.Touched("email"), false, "Expected email not to be touched.")
+		assert.Equal(len(lv.TouchedErrors()), 1, "Expected only the error for name to be shown.")
+		assert.Equal(nameEl.Class().Contains("invalid"), true, "Expected the error for name to be rendered.")
+		assert.Equal(emailEl.Class().Contains("invalid"), false, "Expected the error for email not to be rendered.")
+		// Blur events touch the input too.
+		emailEl.Underlying().Call("dispatchEvent", js.Global.Get("Event").New("blur"))
+		assert.Equal(lv.Touched("email"), true, "Expected email to be touched after blur.")
+		assert.Equal(emailEl.Class().Contains("invalid"), true, "Expected the error for email to be rendered after blur.")
+		// Without a delay, input events validate immediately.
+		lv.Delay = 0
+		nameEl.Value = "Bar"
+		dispatchEvent(nameEl, "input")
+		assert.Equal(lv.Form.HasErrorsFor("name"), false, "Expected no error for name after input event.")
+		assert.Equal(nameEl.Class().Contains("invalid"), false, "Expected the rendered error for name to be removed.")
+		lv.Reset()
+		assert.Equal(lv.Touched("name"), false, "Expected name not to be touched after Reset.")
+		assert.Equal(lv.Dirty("name"), false, "Expected name not to be dirty after Reset.")
+		assert.Equal(len(container.QuerySelectorAll("[data-form-error]")), 0, "Expected no rendered errors after Reset.")
+		lv.TouchAll()
+		assert.Equal(len(lv.TouchedErrors()), 1, "Expected all errors to be shown after TouchAll.")
+		// After Close, events no longer trigger validation.
+		lv.Close()
+		count := validations
+		dispatchEvent(nameEl, "change")
+		assert.Equal(validations, count, "Expected no validation after Close.")
 	})
+}
 
-	qunit.Test("InputBinder", func(assert qunit.QUnitAssert) {
-		defer reset()
-		// Create a form with some inputs and values.
-		container.SetInnerHTML(`<form>
-			<input name="name" value="Foo Bar" >
-			</form>`)
-		formEl := container.QuerySelector("form")
-		form, err := form.Parse(formEl)
-		assertNoError(assert, err, "")
-		// Bind the form to our CustomInputBinder type and check the results.
-		binder := &CustomInputBinder{}
-		err = form.Bind(binder)
-		assertNoError(assert, err, "")
-		assert.Equal(binder.Name.First, "Foo", "binder.Name.First was not correct.")
-		assert.Equal(binder.Name.Last, "Bar", "binder.Name.Last was not correct.")
+// dispatchEvent dispatches a new bubbling event with the given type on el.
+func dispatchEvent(el dom.Element, eventType string) {
+	event := js.Global.Get("Event").New(eventType, map[string]interface{}{
+		"bubbles": true,
 	})
+	el.Underlying().Call("dispatchEvent", event)
 }
 
 func mustParseTime(layout string, value string) time.Time {
@@ -0,0 +1,356 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+//go:build js
+// +build js
+
+package form
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gopherjs/gopherjs/js"
+	"honnef.co/go/js/dom"
+)
+
+// liveBindingEvents are the events which cause a LiveBinding to re-bind the
+// form. The input event fires on every keystroke, and the change event covers
+// elements which do not fire input events in every browser (e.g. checkboxes
+// and selects).
+var liveBindingEvents = []string{"input", "change"}
+
+// LiveBinding is a live, two-way binding between an html form element and a
+// struct. Unlike Bind, which performs a one-time binding, a LiveBinding listens
+// for input and change events on the form element and re-binds the struct
+// every time the user changes an input. Changes to the struct can be pushed to
+// the form element with Update. Call Close to stop listening for events.
+type LiveBinding struct {
+	// Form is the Form that was most recently parsed from the form element. It
+	// includes any errors added by the validation function.
+	Form *Form
+	// Err is the error returned by the most recent call to Bind, if any.
+	Err error
+	// OnChange, if non-nil, is called after the struct has been re-bound (and
+	// validated) in response to an event. It receives the newly parsed Form and
+	// the error returned from Bind, if any.
+	OnChange  func(form *Form, err error)
+	formEl    *dom.HTMLFormElement
+	v         interface{}
+	validate  func(*Form)
+	listeners map[string]func(*js.Object)
+}
+
+// BindLive creates a LiveBinding between formElement, which must be a
+// *dom.HTMLFormElement, and v, which must be a pointer to a struct (see Bind).
+// It binds the current input values to v immediately and then again every time
+// the user changes an input. If validate is non-nil, it is called with the
+// newly parsed Form each time, before v is bound, so that validation errors are
+// available via the Form field. BindLive returns an error if formElement is
+// not a form element, if it cannot be parsed, or if v is not a pointer to a
+// struct. Errors for individual inputs (i.e. BindErrors) do not cause BindLive
+// to fail, since the user may not have filled out the form yet. Instead, they
+// are stored in the Err field, just like after any later change.
+func BindLive(formElement dom.Element, v interface{}, validate func(*Form)) (*LiveBinding, error) {
+	formEl, ok := formElement.(*dom.HTMLFormElement)
+	if !ok {
+		return nil, fmt.Errorf("form: Argument to BindLive must be a *dom.HTMLFormElement. (Got %T)", formElement)
+	}
+	binding := &LiveBinding{
+		formEl:    formEl,
+		v:         v,
+		validate:  validate,
+		listeners: map[string]func(*js.Object){},
+	}
+	if err := binding.rebind(); err != nil {
+		if _, ok := err.(BindErrors); !ok {
+			return nil, err
+		}
+	}
+	for _, eventType := range liveBindingEvents {
+		binding.listeners[eventType] = formEl.AddEventListener(eventType, false, func(dom.Event) {
+			binding.rebind()
+			if binding.OnChange != nil {
+				binding.OnChange(binding.Form, binding.Err)
+			}
+		})
+	}
+	return binding, nil
+}
+
+// rebind parses the form element, runs the validation function, and binds
+// the result to the struct. It stores the results in binding.Form and
+// binding.Err and also returns the error.
+func (binding *LiveBinding) rebind() error {
+	form, err := Parse(binding.formEl)
+	if err != nil {
+		binding.Err = err
+		return err
+	}
+	if binding.validate != nil {
+		binding.validate(form)
+	}
+	binding.Form = form
+	binding.Err = form.Bind(binding.v)
+	return binding.Err
+}
+
+// Update pushes the current values of the struct to the form element, using
+// the same rules as Fill. Call Update after changing the struct in code so that
+// the form element reflects the changes. Update does not trigger OnChange.
+func (binding *LiveBinding) Update() error {
+	form, err := Parse(binding.formEl)
+	if err != nil {
+		return err
+	}
+	if err := form.Fill(binding.v); err != nil {
+		return err
+	}
+	binding.Form = form
+	return nil
+}
+
+// Close tears down the binding by removing its event listeners from the form
+// element. After Close, changes to the form element no longer affect the
+// struct. It is safe to call Close more than once.
+func (binding *LiveBinding) Close() {
+	for eventType, listener := range binding.listeners {
+		binding.formEl.RemoveEventListener(eventType, false, listener)
+	}
+	binding.listeners = map[string]func(*js.Object){}
+}
+
+// DefaultLiveValidationDelay is the default value of LiveValidation.Delay.
+const DefaultLiveValidationDelay = 300 * time.Millisecond
+
+// liveEvent is an event type which a LiveValidation listens for, together with
+// whether it listens in the capture phase.
+type liveEvent struct {
+	eventType  string
+	useCapture bool
+}
+
+// liveValidationEvents are the events which cause a LiveValidation to
+// validate the form again. The blur event does not bubble, so it is handled in
+// the capture phase instead.
+var liveValidationEvents = []liveEvent{
+	{"input", false},
+	{"change", false},
+	{"blur", true},
+}
+
+// LiveValidation validates an html form element every time the user changes
+// or leaves an input. It parses the form element and runs the validation
+// function on input, change, and blur events. Input events, which fire on
+// every keystroke, are debounced, so the form is only validated once the
+// user stops typing for Delay. Change and blur events validate the form
+// immediately.
+//
+// LiveValidation tracks which inputs the user has interacted with. An input
+// becomes touched when it loses focus or fires a change event, and dirty when
+// its value differs from its value when the LiveValidation was created or
+// last reset. Errors for inputs which are not touched are kept in Form.Errors
+// but are not shown, so users don't see errors for inputs they haven't filled
+// in yet. Call Close to stop listening for events.
+type LiveValidation struct {
+	// Form is the Form that was most recently parsed and validated. Its Errors
+	// include the errors for all inputs, whether they are touched or not, so
+	// Form.HasErrors tells you whether the form is valid.
+	Form *Form
+	// Renderer, if non-nil, is used to render the errors for the touched inputs
+	// after every validation (see TouchedErrors).
+	Renderer *ErrorRenderer
+	// Delay is how long to wait after the last input event before validating
+	// the form. If it is zero or negative, input events validate the form
+	// immediately.
+	Delay time.Duration
+	// OnChange, if non-nil, is called after every validation with the newly
+	// validated Form.
+	OnChange      func(form *Form)
+	formEl        *dom.HTMLFormElement
+	validate      func(*Form)
+	listeners     map[liveEvent]func(*js.Object)
+	initialValues map[string][]string
+	touched       map[string]bool
+	timer         *time.Timer
+}
+
+// ValidateLive creates a LiveValidation for formElement, which must be a
+// *dom.HTMLFormElement, using validate to validate the form. It parses and
+// validates the form immediately, so that Form is never nil, and then again
+// in response to events. The returned LiveValidation uses
+// DefaultLiveValidationDelay and has no Renderer. Set its fields to change
+// that. ValidateLive returns an error if formElement is not a form element.
+func ValidateLive(formElement dom.Element, validate func(*Form)) (*LiveValidation, error) {
+	formEl, ok := formElement.(*dom.HTMLFormElement)
+	if !ok {
+		return nil, fmt.Errorf("form: Argument to ValidateLive must be a *dom.HTMLFormElement. (Got %T)", formElement)
+	}
+	lv := &LiveValidation{
+		Delay:     DefaultLiveValidationDelay,
+		formEl:    formEl,
+		validate:  validate,
+		listeners: map[liveEvent]func(*js.Object){},
+		touched:   map[string]bool{},
+	}
+	if err := lv.revalidate(); err != nil {
+		return nil, err
+	}
+	lv.initialValues = formValues(lv.Form)
+	for _, event := range liveValidationEvents {
+		lv.listeners[event] = formEl.AddEventListener(event.eventType, event.useCapture, lv.handleEvent)
+	}
+	return lv, nil
+}
+
+// handleEvent validates the form in response to an event.
+func (lv *LiveValidation) handleEvent(event dom.Event) {
+	if event.Type() == "input" && lv.Delay > 0 {
+		lv.stopTimer()
+		lv.timer = time.AfterFunc(lv.Delay, func() {
+			lv.Validate()
+		})
+		return
+	}
+	if event.Type() != "input" {
+		if name := event.Target().Underlying().Get("name"); name != nil && name != js.Undefined && name.String() != "" {
+			lv.touched[name.String()] = true
+		}
+	}
+	lv.Validate()
+}
+
+// Validate parses and validates the form immediately, cancelling any pending
+// validation for an earlier input event. It renders the errors with Renderer,
+// if any, and calls OnChange, if any.
+func (lv *LiveValidation) Validate() {
+	lv.stopTimer()
+	if err := lv.revalidate(); err != nil {
+		return
+	}
+	if lv.Renderer != nil {
+		lv.Renderer.Render(lv.touchedForm())
+	}
+	if lv.OnChange != nil {
+		lv.OnChange(lv.Form)
+	}
+}
+
+// revalidate parses the form element and runs the validation function. It
+// stores the result in lv.Form.
+func (lv *LiveValidation) revalidate() error {
+	form, err := Parse(lv.formEl)
+	if err != nil {
+		return err
+	}
+	if lv.validate != nil {
+		lv.validate(form)
+	}
+	lv.Form = form
+	return nil
+}
+
+// stopTimer cancels the pending validation for an input event, if any.
+func (lv *LiveValidation) stopTimer() {
+	if lv.timer != nil {
+		lv.timer.Stop()
+		lv.timer = nil
+	}
+}
+
+// Touched returns true iff the input identified by inputName has lost focus or
+// fired a change event since the LiveValidation was created or last reset.
+func (lv *LiveValidation) Touched(inputName string) bool {
+	return lv.touched[inputName]
+}
+
+// Dirty returns true iff the values of the inputs identified by inputName (see
+// Form.GetStrings) differ from their values when the LiveValidation was
+// created or last reset.
+func (lv *LiveValidation) Dirty(inputName string) bool {
+	values, _ := lv.Form.GetStrings(inputName)
+	initialValues := lv.initialValues[inputName]
+	if len(values) != len(initialValues) {
+		return true
+	}
+	for i := range values {
+		if values[i] != initialValues[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// TouchedErrors returns the errors in Form which should be shown to the user:
+// the validation errors for touched inputs, and all errors which are not
+// ValidationErrors.
+func (lv *LiveValidation) TouchedErrors() []error {
+	errs := []error{}
+	for _, err := range lv.Form.Errors {
+		if valErr, ok := asValidationError(err); ok && !lv.touched[valErr.InputName] {
+			continue
+		}
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// touchedForm returns a copy of Form which only contains the touched inputs
+// and TouchedErrors, for use with Renderer.
+func (lv *LiveValidation) touchedForm() *Form {
+	form := NewForm()
+	for name := range lv.touched {
+		if group, found := lv.Form.Groups[name]; found {
+			form.Groups[name] = group
+			form.Inputs[name] = lv.Form.Inputs[name]
+		}
+	}
+	form.Errors = lv.TouchedErrors()
+	return form
+}
+
+// TouchAll marks every input in the form as touched and validates the form
+// immediately, so that all errors are shown. It is useful when the user tries
+// to submit the form.
+func (lv *LiveValidation) TouchAll() {
+	for name := range lv.Form.Groups {
+		lv.touched[name] = true
+	}
+	lv.Validate()
+}
+
+// Reset marks every input as untouched and records the current values as the
+// values against which Dirty compares, e.g. after the form was submitted or
+// filled with new values. It removes any rendered errors and validates the
+// form immediately.
+func (lv *LiveValidation) Reset() {
+	if lv.Renderer != nil {
+		lv.Renderer.Clear(lv.Form)
+	}
+	lv.touched = map[string]bool{}
+	lv.Validate()
+	lv.initialValues = formValues(lv.Form)
+}
+
+// Close removes the event listeners of the LiveValidation from the form
+// element and cancels any pending validation. It is safe to call Close more
+// than once.
+func (lv *LiveValidation) Close() {
+	lv.stopTimer()
+	for event, listener := range lv.listeners {
+		lv.formEl.RemoveEventListener(event.eventType, event.useCapture, listener)
+	}
+	lv.listeners = map[liveEvent]func(*js.Object){}
+}
+
+// formValues returns the values of all the inputs in form, keyed by input
+// name (see Form.GetStrings).
+func formValues(form *Form) map[string][]string {
+	values := map[string][]string{}
+	for name := range form.Groups {
+		values[name], _ = form.GetStrings(name)
+	}
+	return values
+}
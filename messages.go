@@ -0,0 +1,247 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Catalog holds message templates for validation errors, keyed by locale and
+// rule code (see ValidationError.Code). Assign a Catalog to Form.Catalog to
+// replace the default English messages of the built-in validations.
+//
+// A template may contain placeholders in curly braces: {label} is replaced by
+// the label of the input (see Form.Label), {name} by the name of the input, and
+// any other placeholder by the rule parameter with the same key (see
+// ValidationError.Params). For example:
+//
+//	catalog := form.NewCatalog()
+//	catalog.Add("en", map[string]string{
+//		form.RuleRequired:  "Please fill in {label}.",
+//		form.RuleMaxLength: "{label} can be at most {limit} characters long.",
+//	})
+//	catalog.Add("de", map[string]string{
+//		form.RuleRequired:  "Bitte füllen Sie {label} aus.",
+//		form.RuleMaxLength: "{label} darf höchstens {limit} Zeichen lang sein.",
+//	})
+//
+// Lists are joined with commas, times are formatted according to the type of
+// the input, and the "other" and "others" parameters of the cross-field rules
+// are replaced by the labels of the other inputs. Placeholders which do not
+// match a parameter are left unchanged.
+type Catalog struct {
+	// Fallback is the locale which is used when a template is missing for the
+	// requested locale. If there is no template in either locale, the default
+	// message is used.
+	Fallback  string
+	templates map[string]map[string]string
+}
+
+// NewCatalog creates and returns an empty Catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		templates: map[string]map[string]string{},
+	}
+}
+
+// Add adds templates, keyed by rule code, for the given locale. Templates which
+// were previously added for the same locale and code are replaced.
+func (c *Catalog) Add(locale string, templates map[string]string) {
+	if c.templates == nil {
+		c.templates = map[string]map[string]string{}
+	}
+	if c.templates[locale] == nil {
+		c.templates[locale] = map[string]string{}
+	}
+	for code, template := range templates {
+		c.templates[locale][code] = template
+	}
+}
+
+// Template returns the template for the given locale and rule code and true,
+// or false if there is none. If there is no template for a regional locale
+// such as "pt-BR", Template tries the base language ("pt") and then the
+// Fallback locale.
+func (c *Catalog) Template(locale string, code string) (string, bool) {
+	locales := []string{locale}
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locales = append(locales, locale[:i])
+	}
+	if c.Fallback != "" {
+		locales = append(locales, c.Fallback)
+	}
+	for _, l := range locales {
+		if template, found := c.templates[l][code]; found {
+			return template, true
+		}
+	}
+	return "", false
+}
+
+// The suffixes of the keys in defaultFormats for the variants of a message.
+const (
+	floatVariant   = "/float"   // The limit is a float64.
+	timeVariant    = "/time"    // The inputs are compared as times.
+	baseVariant    = "/base"    // The step has a base other than 0.
+	schemesVariant = "/schemes" // The URL must start with one of the schemes.
+)
+
+// defaultFormats holds the format strings of the default English messages of
+// the built-in validations and of Form.AddBindErrors, keyed by rule code. Rules
+// whose message depends on the type of the input or on the parameters have
+// additional variants, keyed by the rule code followed by one of the variant
+// suffixes. The first argument of each format is the label of the input, and
+// the remaining arguments are the parameters in the order they appear in the
+// message. A template in the form's Catalog replaces all the variants for the
+// rule.
+var defaultFormats = map[string]string{
+	RuleRequired:                      "%s is required.",
+	RuleInteger:                       "%s must be an integer.",
+	RuleNumber:                        "%s must be a number.",
+	RuleBool:                          "%s must be either true or false.",
+	RuleTime:                          "%s must be a valid time.",
+	RuleLess:                          "%s must be less than %d.",
+	RuleLess + floatVariant:           "%s must be less than %f.",
+	RuleLessOrEqual:                   "%s must be less than or equal to %d.",
+	RuleLessOrEqual + floatVariant:    "%s must be less than or equal to %f.",
+	RuleGreater:                       "%s must be greater than %d.",
+	RuleGreater + floatVariant:        "%s must be greater than %f.",
+	RuleGreaterOrEqual:                "%s must be greater than or equal to %d.",
+	RuleGreaterOrEqual + floatVariant: "%s must be greater than or equal to %f.",
+	RuleStep:                          "%s must be a multiple of %v.",
+	RuleStep + baseVariant:            "%s must be %v plus a multiple of %v.",
+	RuleMinLength:                     "%s must be at least %d characters long.",
+	RuleMaxLength:                     "%s must be at most %d characters long.",
+	RuleLength:                        "%s must be exactly %d characters long.",
+	RulePattern:                       "%s is not in the correct format.",
+	RuleOneOf:                         "%s must be one of: %s.",
+	RuleNotOneOf:                      "%s must not be one of: %s.",
+	RuleEmail:                         "%s must be a valid email address.",
+	RuleURL:                           "%s must be a valid URL.",
+	RuleURL + schemesVariant:          "%s must be a valid URL starting with one of: %s.",
+	RuleTel:                           "%s must be a valid phone number.",
+	RuleHexColor:                      "%s must be a color in the format #rrggbb.",
+	RuleUUID:                          "%s must be a valid UUID.",
+	RuleBefore:                        "%s must be before %s.",
+	RuleAfter:                         "%s must be after %s.",
+	RuleBetween:                       "%s must be between %s and %s.",
+	RuleNotInFuture:                   "%s must not be in the future.",
+	RuleNotInPast:                     "%s must not be in the past.",
+	RuleMin:                           "%s must be greater than or equal to %s.",
+	RuleMin + timeVariant:             "%s must not be before %s.",
+	RuleMax:                           "%s must be less than or equal to %s.",
+	RuleMax + timeVariant:             "%s must not be after %s.",
+	RuleRange:                         "%s must be between %s and %s.",
+	RuleEqualTo:                       "%s must be equal to %s.",
+	RuleGreaterThan:                   "%s must be greater than %s.",
+	RuleGreaterThan + timeVariant:     "%s must be after %s.",
+	RuleLessThan:                      "%s must be less than %s.",
+	RuleLessThan + timeVariant:        "%s must be before %s.",
+	RuleRequiredIf:                    "%s is required when %s is %s.",
+	RuleRequiredUnless:                "%s is required unless %s is %s.",
+	RuleRequiredWith:                  "%s is required when %s is present.",
+	RuleWeek:                          "%s must be a valid week.",
+	RuleInvalid:                       "%s is invalid.",
+}
+
+// placeholderRegexp matches the placeholders in a template.
+var placeholderRegexp = regexp.MustCompile(`\{[a-zA-Z_][a-zA-Z0-9_]*\}`)
+
+// message returns the message for a validation error with the given rule for
+// the input identified by inputName, using the form's catalog and locale. It
+// returns false if the form has no catalog or the catalog has no template for
+// the rule.
+func (form *Form) message(inputName string, r rule) (string, bool) {
+	if form.Catalog == nil || r.code == "" {
+		return "", false
+	}
+	template, found := form.Catalog.Template(form.Locale, r.code)
+	if !found {
+		return "", false
+	}
+	return placeholderRegexp.ReplaceAllStringFunc(template, func(placeholder string) string {
+		key := placeholder[1 : len(placeholder)-1]
+		switch key {
+		case "label":
+			return form.Label(inputName)
+		case "name":
+			return inputName
+		}
+		param, found := r.params[key]
+		if !found {
+			return placeholder
+		}
+		return form.formatParam(inputName, key, param)
+	}), true
+}
+
+// formatParam formats a rule parameter for use in a message.
+func (form *Form) formatParam(inputName string, key string, param interface{}) string {
+	switch p := param.(type) {
+	case string:
+		if key == "other" {
+			return form.Label(p)
+		}
+		return p
+	case []string:
+		if key == "others" {
+			labels := make([]string, len(p))
+			for i, name := range p {
+				labels[i] = form.Label(name)
+			}
+			return strings.Join(labels, ", ")
+		}
+		return strings.Join(p, ", ")
+	case float64:
+		return strconv.FormatFloat(p, 'f', -1, 64)
+	case time.Time:
+		inputType := InputDefault
+		if input, found := form.Inputs[inputName]; found {
+			inputType = input.Type
+		}
+		return formatTime(p, inputType)
+	}
+	return fmt.Sprint(param)
+}
+
+// labeledElement is an Element which knows its own label, e.g. from a label
+// element in the DOM.
+type labeledElement interface {
+	Label() string
+}
+
+// Label returns a human-readable label for the input identified by inputName,
+// which is used instead of the name in the messages of the built-in
+// validations. The label is the first non-empty one of:
+//
+//  1. The entry for inputName in form.Labels.
+//  2. The data-label attribute of the input's element.
+//  3. The label provided by the input's element, if it has a Label method. For
+//     forms created by Parse, this is the text of the associated label
+//     element.
+//  4. inputName itself.
+func (form *Form) Label(inputName string) string {
+	if label := form.Labels[inputName]; label != "" {
+		return label
+	}
+	input, found := form.Inputs[inputName]
+	if !found || input.El == nil {
+		return inputName
+	}
+	if label := input.El.GetAttribute("data-label"); label != "" {
+		return label
+	}
+	if el, ok := input.El.(labeledElement); ok {
+		if label := el.Label(); label != "" {
+			return label
+		}
+	}
+	return inputName
+}
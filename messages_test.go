@@ -0,0 +1,161 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newTestCatalog returns a catalog with english and german templates for some
+// of the rules.
+func newTestCatalog() *Catalog {
+	catalog := NewCatalog()
+	catalog.Add("en", map[string]string{
+		RuleRequired:   "Please fill in {label}.",
+		RuleMaxLength:  "{label} can be at most {limit} characters long.",
+		RuleOneOf:      "{label} must be {options}.",
+		RuleInteger:    "{label} must be a whole number.",
+		RuleRequiredIf: "{label} is required for {other} {value}.",
+		RuleBefore:     "{label} must be before {limit}.",
+		RuleStep:       "{label} must be in steps of {step}.",
+		RuleEmail:      "{label} is not an email address ({unknown}).",
+	})
+	catalog.Add("de", map[string]string{
+		RuleRequired: "Bitte füllen Sie {label} aus.",
+	})
+	return catalog
+}
+
+func TestCatalogTemplate(t *testing.T) {
+	catalog := newTestCatalog()
+	catalog.Fallback = "en"
+	testCases := []struct {
+		locale, code, expected string
+		found                  bool
+	}{
+		{"de", RuleRequired, "Bitte füllen Sie {label} aus.", true},
+		{"de-AT", RuleRequired, "Bitte füllen Sie {label} aus.", true},
+		{"de_CH", RuleRequired, "Bitte füllen Sie {label} aus.", true},
+		{"de", RuleMaxLength, "{label} can be at most {limit} characters long.", true},
+		{"fr", RuleRequired, "Please fill in {label}.", true},
+		{"en", RuleUUID, "", false},
+	}
+	for _, tc := range testCases {
+		got, found := catalog.Template(tc.locale, tc.code)
+		if got != tc.expected || found != tc.found {
+			t.Errorf("Template(%q, %q): expected %q, %v but got %q, %v", tc.locale, tc.code, tc.expected, tc.found, got, found)
+		}
+	}
+	catalog.Fallback = ""
+	if _, found := catalog.Template("fr", RuleRequired); found {
+		t.Error("Expected no template for fr without a fallback")
+	}
+}
+
+func TestCatalogMessages(t *testing.T) {
+	newForm := func() *Form {
+		form := NewForm(
+			newTestInput("name", InputText, "Foo Bar"),
+			newTestInput("country", InputText, "US"),
+			NewInput(testElement{name: "age", typ: InputNumber, value: "4.5", attrs: map[string]string{"data-label": "Your age"}}),
+			newTestInput("date", InputDate, "2015-07-01"),
+			newTestInput("email", InputEmail, "foo"),
+		)
+		form.Catalog = newTestCatalog()
+		form.Locale = "en"
+		form.Labels = map[string]string{"name": "Full name", "country": "Country", "state": "State"}
+		return form
+	}
+	testCases := []struct {
+		validate func(form *Form)
+		expected string
+	}{
+		{func(form *Form) { form.Validate("missing").Required() }, "Please fill in missing."},
+		{func(form *Form) { form.Validate("name").MaxLength(3) }, "Full name can be at most 3 characters long."},
+		{func(form *Form) { form.Validate("name").OneOf([]string{"a", "b"}) }, "Full name must be a, b."},
+		{func(form *Form) { form.Validate("age").IsInt() }, "Your age must be a whole number."},
+		{func(form *Form) { form.Validate("age").StepFloat(2, 0) }, "Your age must be in steps of 2."},
+		{func(form *Form) { form.Validate("state").RequiredIf("country", "US") }, "State is required for Country US."},
+		{func(form *Form) { form.Validate("date").Before(mustParseTime("2006-01-02", "2015-06-01")) }, "date must be before 2015-06-01."},
+		{func(form *Form) { form.Validate("email").IsType() }, "email is not an email address ({unknown})."},
+		// Rules without a template use the default message with the label.
+		{func(form *Form) { form.Validate("name").MinLength(10) }, "Full name must be at least 10 characters long."},
+		// Custom messages are never replaced.
+		{func(form *Form) { form.Validate("missing").Requiredf("custom") }, "custom"},
+		// But type errors from custom validations use the catalog.
+		{func(form *Form) { form.Validate("age").Lessf(10, "custom") }, "Your age must be a whole number."},
+		// Validations called after a custom validation are still localized.
+		{func(form *Form) { form.Validate("missing").Requiredf("custom").Required() }, "Please fill in missing."},
+		// Bind errors use the catalog too.
+		{func(form *Form) {
+			target := struct{ Age int }{}
+			form.AddBindErrors(form.Bind(&target).(BindErrors))
+		}, "Your age must be a whole number."},
+	}
+	for i, tc := range testCases {
+		form := newForm()
+		tc.validate(form)
+		if len(form.Errors) == 0 {
+			t.Errorf("Test case %d: expected an error but got none", i)
+			continue
+		}
+		if got := form.Errors[len(form.Errors)-1].Error(); got != tc.expected {
+			t.Errorf("Test case %d: expected %q but got %q", i, tc.expected, got)
+		}
+	}
+}
+
+func TestLabel(t *testing.T) {
+	form := NewForm(
+		newTestInput("plain", InputText, ""),
+		NewInput(testElement{name: "data", typ: InputText, attrs: map[string]string{"data-label": "Data label"}}),
+		NewInput(testElement{name: "both", typ: InputText, attrs: map[string]string{"data-label": "Data label"}}),
+	)
+	form.Labels = map[string]string{"both": "Explicit label", "missing": "Missing label"}
+	expectedLabels := map[string]string{
+		"plain":   "plain",
+		"data":    "Data label",
+		"both":    "Explicit label",
+		"missing": "Missing label",
+		"other":   "other",
+	}
+	for name, expected := range expectedLabels {
+		if got := form.Label(name); got != expected {
+			t.Errorf("Incorrect label for %s. Expected %q but got %q", name, expected, got)
+		}
+	}
+	// Default messages use the label.
+	form.Validate("data").Required()
+	if got := form.Errors[0].Error(); got != "Data label is required." {
+		t.Errorf("Expected the default message to use the label but got %q", got)
+	}
+}
+
+func TestDefaultFormats(t *testing.T) {
+	codes := []string{
+		RuleRequired, RuleInteger, RuleNumber, RuleBool, RuleTime, RuleLess,
+		RuleLessOrEqual, RuleGreater, RuleGreaterOrEqual, RuleStep, RuleMinLength,
+		RuleMaxLength, RuleLength, RulePattern, RuleOneOf, RuleNotOneOf, RuleEmail,
+		RuleURL, RuleTel, RuleHexColor, RuleUUID, RuleBefore, RuleAfter,
+		RuleBetween, RuleNotInFuture, RuleNotInPast, RuleMin, RuleMax, RuleRange,
+		RuleEqualTo, RuleGreaterThan, RuleLessThan, RuleRequiredIf,
+		RuleRequiredUnless, RuleRequiredWith, RuleWeek, RuleInvalid,
+	}
+	for _, code := range codes {
+		if _, found := defaultFormats[code]; !found {
+			t.Errorf("Expected a default format for rule %q", code)
+		}
+	}
+	// The messages of Form.AddBindErrors should match those of the
+	// corresponding validations.
+	form := NewForm(newTestInput("age", InputNumber, "foo"))
+	form.Validate("age").IsInt()
+	form.AddBindErrors(BindErrors{{Field: "Age", InputName: "age", RawValue: "foo", fieldType: reflect.TypeOf(0)}})
+	if len(form.Errors) != 2 || form.Errors[0].Error() != form.Errors[1].Error() {
+		t.Errorf("Expected the same message from IsInt and AddBindErrors but got %v", form.Errors)
+	}
+}
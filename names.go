@@ -0,0 +1,147 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"sort"
+	"strings"
+)
+
+// pathSegment is a single part of an input name, e.g. "billing" or "street"
+// in the input name "billing.street". Bind matches struct fields to inputs by
+// comparing paths of segments.
+type pathSegment struct {
+	name string
+	// exact is true iff the segment only matches names which are exactly
+	// equal. Otherwise the segment matches names which are equal when ignoring
+	// case.
+	exact bool
+}
+
+// matches returns true iff s matches the segment.
+func (seg pathSegment) matches(s string) bool {
+	if seg.exact {
+		return s == seg.name
+	}
+	return strings.EqualFold(s, seg.name)
+}
+
+// appendPath returns a new path consisting of path followed by segments. It
+// never modifies the underlying array of path.
+func appendPath(path []pathSegment, segments ...pathSegment) []pathSegment {
+	newPath := make([]pathSegment, 0, len(path)+len(segments))
+	newPath = append(newPath, path...)
+	return append(newPath, segments...)
+}
+
+// splitInputName splits an input name into its segments. Segments may be
+// separated by dots or wrapped in brackets, so "billing.street" and
+// "billing[street]" both result in the segments "billing" and "street". Empty
+// brackets result in an empty segment, so "tags[]" results in the segments
+// "tags" and "". If the name is malformed (e.g. it has an unclosed
+// bracket), splitInputName returns the whole name as a single segment.
+func splitInputName(name string) []string {
+	segments := []string{}
+	// start is the index at which the current segment begins.
+	start := 0
+	for i := 0; i < len(name); i++ {
+		switch name[i] {
+		case '.':
+			segments = append(segments, name[start:i])
+			start = i + 1
+		case '[':
+			end := strings.IndexByte(name[i:], ']')
+			if end == -1 {
+				return []string{name}
+			}
+			if i > 0 && name[i-1] != ']' {
+				segments = append(segments, name[start:i])
+			}
+			segments = append(segments, name[i+1:i+end])
+			i += end
+			// A dot directly after a closing bracket is redundant.
+			if i+1 < len(name) && name[i+1] == '.' {
+				i++
+			}
+			if i == len(name)-1 {
+				return segments
+			}
+			start = i + 1
+		}
+	}
+	return append(segments, name[start:])
+}
+
+// pathMatches returns true iff the segments of an input name match path. If
+// exactOnly is true, all segments must be exactly equal, regardless of whether
+// the path segment allows case-insensitive matches.
+func pathMatches(path []pathSegment, segments []string, exactOnly bool) bool {
+	if len(path) != len(segments) {
+		return false
+	}
+	for i, seg := range path {
+		if exactOnly && segments[i] != seg.name {
+			return false
+		}
+		if !seg.matches(segments[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// inputForPath returns the input whose name matches path, or nil if there is no
+// such input. An input whose name matches path exactly is preferred over one
+// which only matches when ignoring case.
+func (form *Form) inputForPath(path []pathSegment) *Input {
+	names := sortedInputNames(form.Inputs)
+	for _, exactOnly := range []bool{true, false} {
+		for _, name := range names {
+			if pathMatches(path, splitInputName(name), exactOnly) {
+				return form.Inputs[name]
+			}
+		}
+	}
+	return nil
+}
+
+// hasInputsWithPrefix returns true iff the form has at least one input whose
+// name has more segments than prefix and begins with segments that match
+// prefix.
+func (form *Form) hasInputsWithPrefix(prefix []pathSegment) bool {
+	for name := range form.Inputs {
+		segments := splitInputName(name)
+		if len(segments) > len(prefix) && pathMatches(prefix, segments[:len(prefix)], false) {
+			return true
+		}
+	}
+	return false
+}
+
+// joinPath returns the canonical input name for path, which is used when there
+// is no input whose name matches path. The segments are separated by dots, and
+// segments which match regardless of case (i.e. field names) are converted to
+// lowercase, so a field named Name results in "name".
+func joinPath(path []pathSegment) string {
+	names := make([]string, len(path))
+	for i, seg := range path {
+		names[i] = seg.name
+		if !seg.exact {
+			names[i] = strings.ToLower(seg.name)
+		}
+	}
+	return strings.Join(names, ".")
+}
+
+// sortedInputNames returns the keys of inputs in sorted order.
+func sortedInputNames(inputs map[string]*Input) []string {
+	names := make([]string, 0, len(inputs))
+	for name := range inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
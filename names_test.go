@@ -0,0 +1,36 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitInputName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected []string
+	}{
+		{"name", []string{"name"}},
+		{"billing.street", []string{"billing", "street"}},
+		{"billing[street]", []string{"billing", "street"}},
+		{"a[b][c]", []string{"a", "b", "c"}},
+		{"a[b].c", []string{"a", "b", "c"}},
+		{"a.b[c]", []string{"a", "b", "c"}},
+		{"items[0].name", []string{"items", "0", "name"}},
+		{"tags[]", []string{"tags", ""}},
+		{"broken[name", []string{"broken[name"}},
+		{"prénom", []string{"prénom"}},
+		{"adresse.rue[numéro]", []string{"adresse", "rue", "numéro"}},
+		{"名前[姓]", []string{"名前", "姓"}},
+	}
+	for _, tc := range testCases {
+		if got := splitInputName(tc.name); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("splitInputName(%q): expected %q but got %q", tc.name, tc.expected, got)
+		}
+	}
+}
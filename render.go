@@ -0,0 +1,230 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+//go:build js
+// +build js
+
+package form
+
+import (
+	"github.com/gopherjs/gopherjs/js"
+	"honnef.co/go/js/dom"
+)
+
+// ErrorRenderer renders the errors in a Form into the DOM. It marks each input
+// as valid or invalid, shows the messages of the validation errors next to the
+// inputs they belong to, and optionally lists all the errors in a summary. Only
+// inputs whose element is a *DOMElement (i.e. forms created by Parse) are
+// rendered. Rendering the same form again, e.g. after validating it again,
+// replaces everything that was rendered before, so the markup never contains
+// stale errors.
+//
+// The messages for an input are written into the element which has a
+// data-error-for attribute equal to the name of the input, if there is one.
+// The form element is searched first, followed by the rest of the document.
+// Otherwise the messages are inserted directly after the input, or after its
+// label if the input is inside a label element, and after the last input for
+// groups of checkboxes and radio buttons. Each message is a span element with
+// MessageClass and a data-form-error attribute equal to the name of the input,
+// which is used to find and remove it later.
+type ErrorRenderer struct {
+	// InvalidClass is added to inputs which have at least one validation error.
+	// If it is empty, no class is added.
+	InvalidClass string
+	// ValidClass is added to inputs which have no validation errors. If it is
+	// empty, no class is added.
+	ValidClass string
+	// MessageClass is the class of the span elements which contain the
+	// messages.
+	MessageClass string
+	// Summary, if non-nil, is the element into which a list of all the errors
+	// in the form is rendered, including errors which are not
+	// ValidationErrors. Its content is replaced by an unordered list with
+	// SummaryClass, or removed if there are no errors.
+	Summary dom.Element
+	// SummaryClass is the class of the list in Summary.
+	SummaryClass string
+}
+
+// NewErrorRenderer creates and returns an ErrorRenderer with the default
+// classes: "invalid", "valid", "form-error", and "form-error-summary".
+func NewErrorRenderer() *ErrorRenderer {
+	return &ErrorRenderer{
+		InvalidClass: "invalid",
+		ValidClass:   "valid",
+		MessageClass: "form-error",
+		SummaryClass: "form-error-summary",
+	}
+}
+
+// Render renders the errors in form into the DOM, replacing any errors which
+// were rendered before. Inputs with at least one ValidationError get
+// InvalidClass and an aria-invalid="true" attribute, and their messages are
+// shown as described for ErrorRenderer. All other inputs get ValidClass.
+// Hidden inputs and buttons are never marked as valid or invalid, but their
+// messages are still shown.
+func (r *ErrorRenderer) Render(form *Form) {
+	r.Clear(form)
+	errs := form.ErrorsByInput()
+	for _, name := range sortedInputNames(form.Inputs) {
+		group := domElements(form.Groups[name])
+		if len(group) == 0 {
+			continue
+		}
+		inputErrs := errs[name]
+		for _, el := range group {
+			if !isMarkable(el.Type()) {
+				continue
+			}
+			if len(inputErrs) > 0 {
+				addClass(el, r.InvalidClass)
+				el.SetAttribute("aria-invalid", "true")
+			} else {
+				addClass(el, r.ValidClass)
+			}
+		}
+		if len(inputErrs) > 0 {
+			r.renderMessages(name, group, inputErrs)
+		}
+	}
+	r.renderSummary(form.Errors)
+}
+
+// Clear removes everything that Render added for form from the DOM: the
+// classes and aria-invalid attributes of the inputs, the messages, and the
+// summary.
+func (r *ErrorRenderer) Clear(form *Form) {
+	for name, inputs := range form.Groups {
+		group := domElements(inputs)
+		for _, el := range group {
+			removeClass(el, r.InvalidClass)
+			removeClass(el, r.ValidClass)
+			el.RemoveAttribute("aria-invalid")
+		}
+		for _, root := range messageRoots(group) {
+			for _, msg := range root.QuerySelectorAll("[data-form-error]") {
+				if msg.GetAttribute("data-form-error") == name {
+					msg.ParentNode().RemoveChild(msg)
+				}
+			}
+		}
+	}
+	if r.Summary != nil {
+		r.Summary.SetInnerHTML("")
+	}
+}
+
+// renderMessages shows the messages for the input identified by name, whose
+// elements are group.
+func (r *ErrorRenderer) renderMessages(name string, group []*DOMElement, errs []*ValidationError) {
+	last := group[len(group)-1]
+	doc := last.OwnerDocument()
+	var parent, before dom.Node
+	if container := errorContainer(name, group); container != nil {
+		parent = container
+	} else {
+		// Insert the messages after the last input, or after its label.
+		var anchor dom.Node = last
+		if label, ok := last.ParentElement().(*dom.HTMLLabelElement); ok {
+			anchor = label
+		}
+		parent = anchor.ParentNode()
+		before = anchor.NextSibling()
+	}
+	for _, err := range errs {
+		msg := doc.CreateElement("span")
+		addClass(msg, r.MessageClass)
+		msg.SetAttribute("data-form-error", name)
+		msg.SetTextContent(err.Error())
+		parent.InsertBefore(msg, before)
+	}
+}
+
+// renderSummary renders a list of errs into r.Summary, if it is non-nil.
+func (r *ErrorRenderer) renderSummary(errs []error) {
+	if r.Summary == nil || len(errs) == 0 {
+		return
+	}
+	doc := r.Summary.OwnerDocument()
+	list := doc.CreateElement("ul")
+	addClass(list, r.SummaryClass)
+	for _, err := range errs {
+		item := doc.CreateElement("li")
+		item.SetTextContent(err.Error())
+		list.AppendChild(item)
+	}
+	r.Summary.AppendChild(list)
+}
+
+// domElements returns the elements of the inputs which are backed by a
+// *DOMElement.
+func domElements(inputs []*Input) []*DOMElement {
+	els := []*DOMElement{}
+	for _, input := range inputs {
+		if el, ok := input.El.(*DOMElement); ok {
+			els = append(els, el)
+		}
+	}
+	return els
+}
+
+// isMarkable returns true iff an ErrorRenderer should mark an input of the
+// given type as valid or invalid.
+func isMarkable(inputType InputType) bool {
+	switch inputType {
+	case InputHidden, InputButton, InputSubmit, InputReset, InputImage:
+		return false
+	}
+	return true
+}
+
+// querySelectorAller is implemented by both dom.Element and dom.Document.
+type querySelectorAller interface {
+	QuerySelectorAll(selectors string) []dom.Element
+}
+
+// messageRoots returns the nodes which are searched for error containers and
+// messages for the inputs in group: the form element which the inputs belong
+// to, if any, and the document.
+func messageRoots(group []*DOMElement) []querySelectorAller {
+	if len(group) == 0 {
+		return nil
+	}
+	roots := []querySelectorAller{}
+	if formEl := group[0].Underlying().Get("form"); formEl != nil && formEl != js.Undefined {
+		roots = append(roots, dom.WrapElement(formEl))
+	}
+	return append(roots, group[0].OwnerDocument())
+}
+
+// errorContainer returns the element which has a data-error-for attribute
+// equal to name, or nil if there is none. It searches the roots returned by
+// messageRoots in order.
+func errorContainer(name string, group []*DOMElement) dom.Element {
+	for _, root := range messageRoots(group) {
+		// Compare the attribute values directly so that names don't need to be
+		// escaped in the selector.
+		for _, el := range root.QuerySelectorAll("[data-error-for]") {
+			if el.GetAttribute("data-error-for") == name {
+				return el
+			}
+		}
+	}
+	return nil
+}
+
+// addClass adds class to el, unless class is empty.
+func addClass(el dom.Element, class string) {
+	if class != "" {
+		el.Class().Add(class)
+	}
+}
+
+// removeClass removes class from el, unless class is empty.
+func removeClass(el dom.Element, class string) {
+	if class != "" {
+		el.Class().Remove(class)
+	}
+}
@@ -0,0 +1,367 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ValidateStruct validates the inputs of the form according to the "validate"
+// struct tags of v, which must be a struct or a pointer to a struct. This lets
+// the validation rules for a form live next to the fields they are bound to:
+//
+//	type Person struct {
+//		Name  string `validate:"required,maxlength=50"`
+//		Age   int    `form:"age" validate:"required,min=1,max=99"`
+//		Email string `validate:"required,email"`
+//	}
+//
+//	f.ValidateStruct(Person{})
+//
+// Fields are matched to inputs using exactly the same rules as Bind, including
+// form struct tags and nested structs. The tag is a comma-separated list of
+// rules, each of which calls the InputValidation method with the same name, so
+// the example above adds exactly the same ValidationErrors as:
+//
+//	f.Validate("name").Required().MaxLength(50)
+//	f.Validate("age").Required().GreaterOrEqual(1).LessOrEqual(99)
+//	f.Validate("email").Required().IsEmail()
+//
+// The rules are applied in order. The following rules are supported:
+//
+//	required                 Required
+//	int                      IsInt
+//	float, number            IsFloat
+//	bool                     IsBool
+//	time                     IsTime
+//	type                     IsType
+//	email                    IsEmail
+//	url, url=SCHEMES         IsURL, with space-separated schemes
+//	tel                      IsTel
+//	hexcolor                 IsHexColor
+//	uuid                     IsUUID
+//	notinfuture              NotInFuture
+//	notinpast                NotInPast
+//	gt=N                     Greater or GreaterFloat
+//	gte=N, min=N             GreaterOrEqual or GreaterOrEqualFloat
+//	lt=N                     Less or LessFloat
+//	lte=N, max=N             LessOrEqual or LessOrEqualFloat
+//	step=N                   StepInt or StepFloat, with a base of 0
+//	minlength=N              MinLength
+//	maxlength=N              MaxLength
+//	len=N                    Length
+//	oneof=OPTIONS            OneOf, with space-separated options
+//	notoneof=OPTIONS         NotOneOf, with space-separated options
+//	equalto=NAME             EqualTo
+//	gtfield=NAME             GreaterThanField
+//	ltfield=NAME             LessThanField
+//	requiredif=NAME VALUE    RequiredIf
+//	requiredunless=NAME VAL  RequiredUnless
+//	requiredwith=NAMES       RequiredWith, with space-separated input names
+//	pattern=REGEXP           Matches
+//
+// The numeric rules use the int methods for fields with an integer type and
+// the float methods for all other fields. Because the pattern may contain
+// commas, it must be the last rule in the tag. NAME refers to the name of an
+// input, not of a field. Slices, arrays, and maps cannot have a validate tag.
+//
+// ValidateStruct returns an error if v is not a struct or a pointer to a
+// struct, or if any validate tag is not valid. In that case, no validations
+// are applied at all.
+func (form *Form) ValidateStruct(v interface{}) error {
+	typ := reflect.TypeOf(v)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return fmt.Errorf("form: ValidateStruct expects a struct or a pointer to a struct, but got: %T", v)
+	}
+	fields := []taggedField{}
+	if err := form.collectTaggedFields(typ, nil, "", map[reflect.Type]bool{}, &fields); err != nil {
+		return err
+	}
+	for _, field := range fields {
+		inputName := joinPath(field.path)
+		if input := form.inputForPath(field.path); input != nil {
+			inputName = input.Name
+		}
+		val := form.Validate(inputName)
+		for _, rule := range field.rules {
+			rule(val)
+		}
+	}
+	return nil
+}
+
+// tagRule applies a single rule from a validate struct tag.
+type tagRule func(val *InputValidation)
+
+// taggedField is a struct field with a validate struct tag.
+type taggedField struct {
+	// path holds the name segments which the input for the field must match.
+	path  []pathSegment
+	rules []tagRule
+}
+
+// collectTaggedFields appends the fields of typ which have a validate struct
+// tag to fields, descending into embedded and nested structs in the same way as
+// bindStruct. path holds the name segments which correspond to typ itself, and
+// fieldPrefix is the qualified name of typ which is used in errors. visiting
+// holds the nested struct types which are currently being descended into. A
+// recursive type, e.g. a struct with a Parent field which points to the same
+// struct, is only descended into again if there are inputs under the path of
+// the field, so that the recursion ends.
+func (form *Form) collectTaggedFields(typ reflect.Type, path []pathSegment, fieldPrefix string, visiting map[reflect.Type]bool, fields *[]taggedField) error {
+	visiting[typ] = true
+	defer delete(visiting, typ)
+	for _, sf := range structFields(typ) {
+		field, tag := sf.field, sf.tag
+		fieldName := field.Name
+		if fieldPrefix != "" {
+			fieldName = fieldPrefix + "." + field.Name
+		}
+		fieldPath := appendPath(path, fieldSegments(field, tag)...)
+		if isNestedStruct(field.Type) {
+			nestedType := getUnderlyingFieldType(field.Type)
+			if visiting[nestedType] && !form.hasInputsWithPrefix(fieldPath) {
+				continue
+			}
+			if err := form.collectTaggedFields(nestedType, fieldPath, fieldName, visiting, fields); err != nil {
+				return err
+			}
+			continue
+		}
+		tagValue, found := field.Tag.Lookup("validate")
+		if !found {
+			continue
+		}
+		if isCollection(field.Type) {
+			return fmt.Errorf("form: Field %s has a validate tag, but validate tags are not supported for %s fields", fieldName, getUnderlyingFieldType(field.Type).Kind())
+		}
+		rules, err := parseValidateTag(tagValue, field.Type)
+		if err != nil {
+			return fmt.Errorf("form: Invalid validate tag for field %s: %s", fieldName, err)
+		}
+		*fields = append(*fields, taggedField{path: fieldPath, rules: rules})
+	}
+	return nil
+}
+
+// parseValidateTag parses the value of a validate struct tag for a field of
+// the given type and returns the rules in order.
+func parseValidateTag(tagValue string, fieldType reflect.Type) ([]tagRule, error) {
+	rules := []tagRule{}
+	for tagValue != "" {
+		option := tagValue
+		tagValue = ""
+		// The pattern may contain commas, so it always extends to the end of
+		// the tag.
+		if i := strings.Index(option, ","); i >= 0 && !strings.HasPrefix(option, "pattern=") {
+			option, tagValue = option[:i], option[i+1:]
+		}
+		if option == "" {
+			continue
+		}
+		name, arg, hasArg := option, "", false
+		if i := strings.Index(option, "="); i >= 0 {
+			name, arg, hasArg = option[:i], option[i+1:], true
+		}
+		rule, err := parseTagRule(name, arg, hasArg, fieldType)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %s", option, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// parseTagRule returns the rule with the given name and argument. hasArg is
+// true iff the rule was followed by an equals sign.
+func parseTagRule(name string, arg string, hasArg bool, fieldType reflect.Type) (tagRule, error) {
+	if rule, found := simpleTagRules[name]; found {
+		if hasArg {
+			return nil, fmt.Errorf("rule %s does not take an argument", name)
+		}
+		return rule, nil
+	}
+	if name == "url" {
+		schemes := strings.Fields(arg)
+		return func(val *InputValidation) { val.IsURL(schemes...) }, nil
+	}
+	if !hasArg || arg == "" {
+		if _, found := argTagRules[name]; found {
+			return nil, fmt.Errorf("rule %s requires an argument", name)
+		}
+		return nil, fmt.Errorf("unknown rule %s", name)
+	}
+	parse, found := argTagRules[name]
+	if !found {
+		return nil, fmt.Errorf("unknown rule %s", name)
+	}
+	return parse(name, arg, fieldType)
+}
+
+// simpleTagRules holds the rules which do not take an argument, keyed by name.
+var simpleTagRules = map[string]tagRule{
+	"required":    func(val *InputValidation) { val.Required() },
+	"int":         func(val *InputValidation) { val.IsInt() },
+	"float":       func(val *InputValidation) { val.IsFloat() },
+	"number":      func(val *InputValidation) { val.IsFloat() },
+	"bool":        func(val *InputValidation) { val.IsBool() },
+	"time":        func(val *InputValidation) { val.IsTime() },
+	"type":        func(val *InputValidation) { val.IsType() },
+	"email":       func(val *InputValidation) { val.IsEmail() },
+	"tel":         func(val *InputValidation) { val.IsTel() },
+	"hexcolor":    func(val *InputValidation) { val.IsHexColor() },
+	"uuid":        func(val *InputValidation) { val.IsUUID() },
+	"notinfuture": func(val *InputValidation) { val.NotInFuture() },
+	"notinpast":   func(val *InputValidation) { val.NotInPast() },
+}
+
+// argTagRules holds functions which parse the rules which take an argument,
+// keyed by name.
+var argTagRules = map[string]func(name string, arg string, fieldType reflect.Type) (tagRule, error){
+	"gt":             parseNumberTagRule,
+	"gte":            parseNumberTagRule,
+	"min":            parseNumberTagRule,
+	"lt":             parseNumberTagRule,
+	"lte":            parseNumberTagRule,
+	"max":            parseNumberTagRule,
+	"step":           parseNumberTagRule,
+	"minlength":      parseLengthTagRule,
+	"maxlength":      parseLengthTagRule,
+	"len":            parseLengthTagRule,
+	"pattern":        parsePatternTagRule,
+	"oneof":          parseListTagRule,
+	"notoneof":       parseListTagRule,
+	"requiredwith":   parseListTagRule,
+	"equalto":        parseFieldTagRule,
+	"gtfield":        parseFieldTagRule,
+	"ltfield":        parseFieldTagRule,
+	"requiredif":     parseFieldValueTagRule,
+	"requiredunless": parseFieldValueTagRule,
+}
+
+// isIntField returns true iff fieldType is an integer type or a pointer to
+// one.
+func isIntField(fieldType reflect.Type) bool {
+	switch getUnderlyingFieldType(fieldType).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// parseNumberTagRule parses one of the rules which compare the value with a
+// number.
+func parseNumberTagRule(name string, arg string, fieldType reflect.Type) (tagRule, error) {
+	if isIntField(fieldType) {
+		limit, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not an integer", arg)
+		}
+		switch name {
+		case "gt":
+			return func(val *InputValidation) { val.Greater(limit) }, nil
+		case "gte", "min":
+			return func(val *InputValidation) { val.GreaterOrEqual(limit) }, nil
+		case "lt":
+			return func(val *InputValidation) { val.Less(limit) }, nil
+		case "lte", "max":
+			return func(val *InputValidation) { val.LessOrEqual(limit) }, nil
+		}
+		if limit <= 0 {
+			return nil, fmt.Errorf("step must be positive")
+		}
+		return func(val *InputValidation) { val.StepInt(limit, 0) }, nil
+	}
+	limit, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a number", arg)
+	}
+	switch name {
+	case "gt":
+		return func(val *InputValidation) { val.GreaterFloat(limit) }, nil
+	case "gte", "min":
+		return func(val *InputValidation) { val.GreaterOrEqualFloat(limit) }, nil
+	case "lt":
+		return func(val *InputValidation) { val.LessFloat(limit) }, nil
+	case "lte", "max":
+		return func(val *InputValidation) { val.LessOrEqualFloat(limit) }, nil
+	}
+	if !(limit > 0) {
+		return nil, fmt.Errorf("step must be positive")
+	}
+	return func(val *InputValidation) { val.StepFloat(limit, 0) }, nil
+}
+
+// parseLengthTagRule parses one of the rules which check the length of the
+// value.
+func parseLengthTagRule(name string, arg string, fieldType reflect.Type) (tagRule, error) {
+	limit, err := strconv.Atoi(arg)
+	if err != nil || limit < 0 {
+		return nil, fmt.Errorf("%s is not a valid length", arg)
+	}
+	switch name {
+	case "minlength":
+		return func(val *InputValidation) { val.MinLength(limit) }, nil
+	case "maxlength":
+		return func(val *InputValidation) { val.MaxLength(limit) }, nil
+	}
+	return func(val *InputValidation) { val.Length(limit) }, nil
+}
+
+// parsePatternTagRule parses the pattern rule.
+func parsePatternTagRule(name string, arg string, fieldType reflect.Type) (tagRule, error) {
+	pattern, err := regexp.Compile(arg)
+	if err != nil {
+		return nil, err
+	}
+	return func(val *InputValidation) { val.Matches(pattern) }, nil
+}
+
+// parseListTagRule parses one of the rules which take a space-separated list.
+func parseListTagRule(name string, arg string, fieldType reflect.Type) (tagRule, error) {
+	list := strings.Fields(arg)
+	switch name {
+	case "oneof":
+		return func(val *InputValidation) { val.OneOf(list) }, nil
+	case "notoneof":
+		return func(val *InputValidation) { val.NotOneOf(list) }, nil
+	}
+	return func(val *InputValidation) { val.RequiredWith(list...) }, nil
+}
+
+// parseFieldTagRule parses one of the rules which compare the value with the
+// value of another input.
+func parseFieldTagRule(name string, arg string, fieldType reflect.Type) (tagRule, error) {
+	switch name {
+	case "equalto":
+		return func(val *InputValidation) { val.EqualTo(arg) }, nil
+	case "gtfield":
+		return func(val *InputValidation) { val.GreaterThanField(arg) }, nil
+	}
+	return func(val *InputValidation) { val.LessThanField(arg) }, nil
+}
+
+// parseFieldValueTagRule parses one of the rules which take the name of
+// another input and a value, separated by a space.
+func parseFieldValueTagRule(name string, arg string, fieldType reflect.Type) (tagRule, error) {
+	parts := strings.SplitN(arg, " ", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return nil, fmt.Errorf("expected an input name and a value separated by a space")
+	}
+	otherName, value := parts[0], parts[1]
+	if name == "requiredif" {
+		return func(val *InputValidation) { val.RequiredIf(otherName, value) }, nil
+	}
+	return func(val *InputValidation) { val.RequiredUnless(otherName, value) }, nil
+}
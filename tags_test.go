@@ -0,0 +1,156 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+type validatedAddress struct {
+	Street string `validate:"required"`
+	Zip    string `form:"zip" validate:"len=5,pattern=^[0-9]+$"`
+}
+
+type validatedPerson struct {
+	Name     string `validate:"required,maxlength=5"`
+	Age      int    `form:"age" validate:"required,min=1,max=99"`
+	Height   float64
+	Weight   float64 `validate:"gt=0,step=0.5"`
+	Email    string  `validate:"required,email"`
+	Password string  `validate:"minlength=8"`
+	Confirm  string  `validate:"equalto=Password"`
+	Color    string  `validate:"oneof=red green blue"`
+	Website  string  `validate:"url=http https"`
+	Secret   string  `form:"-" validate:"required"`
+	Address  validatedAddress
+}
+
+func TestValidateStruct(t *testing.T) {
+	newForm := func() *Form {
+		return NewForm(
+			newTestInput("Name", InputText, "Foo Bar"),
+			newTestInput("age", InputNumber, "100"),
+			newTestInput("weight", InputNumber, "70.25"),
+			newTestInput("email", InputEmail, "foo"),
+			newTestInput("Password", InputPassword, "secret"),
+			newTestInput("confirm", InputPassword, "secreT"),
+			newTestInput("color", InputText, "yellow"),
+			newTestInput("website", InputURL, "ftp://example.com"),
+			newTestInput("address.street", InputText, ""),
+			newTestInput("address.zip", InputText, "12a45"),
+		)
+	}
+	got := newForm()
+	if err := got.ValidateStruct(&validatedPerson{}); err != nil {
+		t.Fatalf("Unexpected error in ValidateStruct: %s", err)
+	}
+	expected := newForm()
+	expected.Validate("Name").Required().MaxLength(5)
+	expected.Validate("age").Required().GreaterOrEqual(1).LessOrEqual(99)
+	expected.Validate("weight").GreaterFloat(0).StepFloat(0.5, 0)
+	expected.Validate("email").Required().IsEmail()
+	expected.Validate("Password").MinLength(8)
+	expected.Validate("confirm").EqualTo("Password")
+	expected.Validate("color").OneOf([]string{"red", "green", "blue"})
+	expected.Validate("website").IsURL("http", "https")
+	expected.Validate("address.street").Required()
+	expected.Validate("address.zip").Length(5).Matches(regexp.MustCompile("^[0-9]+$"))
+	if len(got.Errors) != len(expected.Errors) {
+		t.Fatalf("Expected %d errors but got %d: %v", len(expected.Errors), len(got.Errors), got.Errors)
+	}
+	for i, err := range got.Errors {
+		gotErr, expectedErr := err.(*ValidationError), expected.Errors[i].(*ValidationError)
+		if gotErr.InputName != expectedErr.InputName || gotErr.Error() != expectedErr.Error() ||
+			gotErr.Code != expectedErr.Code || !reflect.DeepEqual(gotErr.Params, expectedErr.Params) {
+			t.Errorf("Error %d: expected %+v but got %+v", i, expectedErr, gotErr)
+		}
+	}
+}
+
+func TestValidateStructMissingInput(t *testing.T) {
+	form := NewForm()
+	if err := form.ValidateStruct(struct {
+		Name string `form:"first-name" validate:"required"`
+	}{}); err != nil {
+		t.Fatalf("Unexpected error in ValidateStruct: %s", err)
+	}
+	if valErr := form.FirstError("first-name"); valErr == nil || valErr.Code != RuleRequired {
+		t.Errorf("Expected a required error for first-name but got %v", form.Errors)
+	}
+
+	// Without a form tag, the input name is the lowercase field name, just
+	// like for Validate.
+	form = NewForm()
+	if err := form.ValidateStruct(struct {
+		Name string `validate:"required"`
+	}{}); err != nil {
+		t.Fatalf("Unexpected error in ValidateStruct: %s", err)
+	}
+	if valErr := form.FirstError("name"); valErr == nil || valErr.Error() != "name is required." {
+		t.Errorf("Expected the error %q for name but got %v", "name is required.", form.Errors)
+	}
+}
+
+type category struct {
+	Name   string `validate:"required"`
+	Parent *category
+}
+
+func TestValidateStructRecursiveType(t *testing.T) {
+	form := NewForm(
+		newTestInput("name", InputText, "Foo"),
+		newTestInput("parent.name", InputText, ""),
+		newTestInput("parent.parent.name", InputText, "Baz"),
+	)
+	if err := form.ValidateStruct(category{}); err != nil {
+		t.Fatalf("Unexpected error in ValidateStruct: %s", err)
+	}
+	if len(form.Errors) != 1 || form.FirstError("parent.name") == nil {
+		t.Errorf("Expected a single error for parent.name but got %v", form.Errors)
+	}
+}
+
+func TestValidateStructErrors(t *testing.T) {
+	testCases := []interface{}{
+		"not a struct",
+		nil,
+		struct {
+			Name string `validate:"foo"`
+		}{},
+		struct {
+			Name string `validate:"required=true"`
+		}{},
+		struct {
+			Age int `validate:"min"`
+		}{},
+		struct {
+			Age int `validate:"min=1.5"`
+		}{},
+		struct {
+			Age float64 `validate:"step=0"`
+		}{},
+		struct {
+			Name string `validate:"pattern=("`
+		}{},
+		struct {
+			State string `validate:"requiredif=country"`
+		}{},
+		struct {
+			Tags []string `validate:"required"`
+		}{},
+	}
+	for i, v := range testCases {
+		form := NewForm(newTestInput("name", InputText, ""))
+		if err := form.ValidateStruct(v); err == nil {
+			t.Errorf("Test case %d: expected an error but got none", i)
+		}
+		if form.HasErrors() {
+			t.Errorf("Test case %d: expected no validation errors but got %v", i, form.Errors)
+		}
+	}
+}
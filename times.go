@@ -0,0 +1,159 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	rfc3339DateLayout                 = "2006-01-02"
+	rfc3339DatetimeLocalLayout        = "2006-01-02T15:04:05.999999999"
+	rfc3339DatetimeLocalMinutesLayout = "2006-01-02T15:04"
+	rfc3339MonthLayout                = "2006-01"
+	clockLayout                       = "15:04"
+	clockSecondsLayout                = "15:04:05.999999999"
+)
+
+// Clock is a time of day without a date or time zone, as used by inputs with
+// the type time. The zero value is midnight.
+type Clock struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+}
+
+// ParseClock parses a time of day in the format used by inputs with the type
+// time, e.g. "15:04", "15:04:05" or "15:04:05.123". It returns an error if s is
+// not a valid time of day.
+func ParseClock(s string) (Clock, error) {
+	layout := clockLayout
+	if len(s) > len(clockLayout) {
+		layout = clockSecondsLayout
+	}
+	// The hour must always have two digits, but time.Parse also accepts one.
+	if len(s) < len(clockLayout) || s[2] != ':' {
+		return Clock{}, fmt.Errorf("form: Invalid time of day: %q", s)
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return Clock{}, fmt.Errorf("form: Invalid time of day: %q", s)
+	}
+	return ClockOf(t), nil
+}
+
+// ClockOf returns the time of day of t in the location of t.
+func ClockOf(t time.Time) Clock {
+	return Clock{
+		Hour:       t.Hour(),
+		Minute:     t.Minute(),
+		Second:     t.Second(),
+		Nanosecond: t.Nanosecond(),
+	}
+}
+
+// On returns the time at which the clock shows c on the same day as date, in
+// the location of date.
+func (c Clock) On(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), c.Hour, c.Minute, c.Second, c.Nanosecond, date.Location())
+}
+
+// String formats c in the format used by inputs with the type time. Seconds and
+// fractions of a second are only included if they are not zero.
+func (c Clock) String() string {
+	t := time.Date(0, time.January, 1, c.Hour, c.Minute, c.Second, c.Nanosecond, time.UTC)
+	if c.Second == 0 && c.Nanosecond == 0 {
+		return t.Format(clockLayout)
+	}
+	return t.Format(clockSecondsLayout)
+}
+
+// Week is an ISO 8601 week, as used by inputs with the type week. Week 1 of a
+// year is the week which contains the first Thursday of that year. The zero
+// value is not a valid week.
+type Week struct {
+	Year int
+	Week int
+}
+
+// ParseWeek parses a week in the format used by inputs with the type week, e.g.
+// "2006-W05". It returns an error if s is not a valid week.
+func ParseWeek(s string) (Week, error) {
+	i := strings.Index(s, "-W")
+	if i < 4 || len(s) != i+4 {
+		return Week{}, fmt.Errorf("form: Invalid week: %q", s)
+	}
+	year, err := strconv.ParseUint(s[:i], 10, 32)
+	if err != nil || year < 1 {
+		return Week{}, fmt.Errorf("form: Invalid week: %q", s)
+	}
+	week, err := strconv.ParseUint(s[i+2:], 10, 8)
+	if err != nil || week < 1 || int(week) > weeksInYear(int(year)) {
+		return Week{}, fmt.Errorf("form: Invalid week: %q", s)
+	}
+	return Week{Year: int(year), Week: int(week)}, nil
+}
+
+// WeekOf returns the ISO 8601 week in which t occurs.
+func WeekOf(t time.Time) Week {
+	year, week := t.ISOWeek()
+	return Week{Year: year, Week: week}
+}
+
+// weeksInYear returns the number of ISO 8601 weeks in the given year, i.e.
+// either 52 or 53. December 28 is always in the last week of the year.
+func weeksInYear(year int) int {
+	_, week := time.Date(year, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return week
+}
+
+// IsZero returns true iff w is the zero value.
+func (w Week) IsZero() bool {
+	return w == Week{}
+}
+
+// Start returns midnight UTC on the Monday which begins w.
+func (w Week) Start() time.Time {
+	// January 4 is always in week 1.
+	jan4 := time.Date(w.Year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	daysSinceMonday := (int(jan4.Weekday()) + 6) % 7
+	return jan4.AddDate(0, 0, (w.Week-1)*7-daysSinceMonday)
+}
+
+// String formats w in the format used by inputs with the type week. The zero
+// value is formatted as an empty string.
+func (w Week) String() string {
+	if w.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%04d-W%02d", w.Year, w.Week)
+}
+
+// formatTime formats t as a value for an input with the given type. It is the
+// inverse of Input.Time. The zero time is formatted as an empty string.
+func formatTime(t time.Time, inputType InputType) string {
+	if t.IsZero() {
+		return ""
+	}
+	switch inputType {
+	case InputDate:
+		return t.Format(rfc3339DateLayout)
+	case InputDateTimeLocal:
+		return t.Format(rfc3339DatetimeLocalLayout)
+	case InputMonth:
+		return t.Format(rfc3339MonthLayout)
+	case InputWeek:
+		return WeekOf(t).String()
+	case InputTime:
+		return ClockOf(t).String()
+	default:
+		return t.Format(time.RFC3339)
+	}
+}
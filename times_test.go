@@ -0,0 +1,142 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseClock(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected Clock
+		valid    bool
+	}{
+		{"00:00", Clock{}, true},
+		{"23:59", Clock{Hour: 23, Minute: 59}, true},
+		{"08:30:15", Clock{Hour: 8, Minute: 30, Second: 15}, true},
+		{"08:30:15.25", Clock{Hour: 8, Minute: 30, Second: 15, Nanosecond: 250000000}, true},
+		{"8:30", Clock{}, false},
+		{"24:00", Clock{}, false},
+		{"08:60", Clock{}, false},
+		{"08:30:", Clock{}, false},
+		{"", Clock{}, false},
+	}
+	for _, tc := range testCases {
+		got, err := ParseClock(tc.value)
+		if !tc.valid {
+			if err == nil {
+				t.Errorf("Expected an error for %q but got none", tc.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %s", tc.value, err)
+			continue
+		}
+		if got != tc.expected {
+			t.Errorf("Incorrect clock for %q. Expected %+v but got %+v", tc.value, tc.expected, got)
+		}
+		if got.String() != tc.value {
+			t.Errorf("Clock did not round trip. Expected %q but got %q", tc.value, got.String())
+		}
+	}
+}
+
+func TestParseWeek(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected Week
+		start    string
+		valid    bool
+	}{
+		{"2015-W01", Week{Year: 2015, Week: 1}, "2014-12-29", true},
+		{"2015-W53", Week{Year: 2015, Week: 53}, "2015-12-28", true},
+		{"2016-W05", Week{Year: 2016, Week: 5}, "2016-02-01", true},
+		{"2014-W53", Week{}, "", false},
+		{"2015-W00", Week{}, "", false},
+		{"2015-W1", Week{}, "", false},
+		{"+201-W01", Week{}, "", false},
+		{"2015-01", Week{}, "", false},
+		{"", Week{}, "", false},
+	}
+	for _, tc := range testCases {
+		got, err := ParseWeek(tc.value)
+		if !tc.valid {
+			if err == nil {
+				t.Errorf("Expected an error for %q but got none", tc.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %s", tc.value, err)
+			continue
+		}
+		if got != tc.expected {
+			t.Errorf("Incorrect week for %q. Expected %+v but got %+v", tc.value, tc.expected, got)
+		}
+		if got.String() != tc.value {
+			t.Errorf("Week did not round trip. Expected %q but got %q", tc.value, got.String())
+		}
+		if start := mustParseTime("2006-01-02", tc.start); !got.Start().Equal(start) {
+			t.Errorf("Incorrect start for %q. Expected %v but got %v", tc.value, start, got.Start())
+		}
+		if WeekOf(got.Start()) != got {
+			t.Errorf("WeekOf(%v) was not %+v", got.Start(), got)
+		}
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	tm := mustParseTime(time.RFC3339, "2015-06-01T08:30:15Z")
+	expectedValues := map[InputType]string{
+		InputDate:          "2015-06-01",
+		InputDateTime:      "2015-06-01T08:30:15Z",
+		InputDateTimeLocal: "2015-06-01T08:30:15",
+		InputMonth:         "2015-06",
+		InputWeek:          "2015-W23",
+		InputTime:          "08:30:15",
+	}
+	for typ, expected := range expectedValues {
+		if got := formatTime(tm, typ); got != expected {
+			t.Errorf("Incorrect value for type %s. Expected %q but got %q", typ, expected, got)
+		}
+	}
+}
+
+func TestInputTime(t *testing.T) {
+	testCases := []struct {
+		typ      InputType
+		value    string
+		expected string
+	}{
+		{InputDate, "2015-06-01", "2015-06-01T00:00:00Z"},
+		{InputDateTimeLocal, "2015-06-01T08:30", "2015-06-01T08:30:00Z"},
+		{InputDateTimeLocal, "2015-06-01T08:30:15", "2015-06-01T08:30:15Z"},
+		{InputDateTimeLocal, "2015-06-01T08:30:15.5", "2015-06-01T08:30:15.5Z"},
+		{InputMonth, "2015-06", "2015-06-01T00:00:00Z"},
+		{InputWeek, "2015-W23", "2015-06-01T00:00:00Z"},
+		{InputTime, "08:30", "0000-01-01T08:30:00Z"},
+	}
+	for _, tc := range testCases {
+		input := Input{RawValue: tc.value, Type: tc.typ}
+		got, err := input.Time()
+		if err != nil {
+			t.Errorf("Unexpected error for %s input %q: %s", tc.typ, tc.value, err)
+			continue
+		}
+		if expected := mustParseTime(time.RFC3339Nano, tc.expected); !got.Equal(expected) {
+			t.Errorf("Incorrect time for %s input %q. Expected %v but got %v", tc.typ, tc.value, expected, got)
+		}
+	}
+	for _, value := range []string{"2015-06-01T08", "2015-06-01 08:30", "2015-06-01"} {
+		input := Input{RawValue: value, Type: InputDateTimeLocal}
+		if _, err := input.Time(); err == nil {
+			t.Errorf("Expected an error for datetime-local input %q but got none", value)
+		}
+	}
+}
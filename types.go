@@ -5,7 +5,9 @@
 
 package form
 
-// InputType is a value for the type attribute of an input element.
+// InputType is a value for the type attribute of an input element. For select
+// and textarea elements, which do not have a type attribute, it is the value of
+// the type property reported by the DOM API.
 type InputType string
 
 const (
@@ -27,9 +29,16 @@ const (
 	InputRange         InputType = "range"
 	InputReset         InputType = "reset"
 	InputSearch        InputType = "search"
+	InputSubmit        InputType = "submit"
 	InputTel           InputType = "tel"
 	InputText          InputType = "text"
 	InputTime          InputType = "time"
 	InputURL           InputType = "url"
 	InputWeek          InputType = "week"
 )
+
+const (
+	InputSelect         InputType = "select-one"
+	InputSelectMultiple InputType = "select-multiple"
+	InputTextArea       InputType = "textarea"
+)
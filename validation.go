@@ -5,7 +5,18 @@
 
 package form
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
 
 // InputValidation is an object which has methods for validating an input. Such
 // methods always return an InputValidation and are chainable. Whenever an input
@@ -16,6 +27,9 @@ type InputValidation struct {
 	Input     *Input
 	Form      *Form
 	InputName string
+	// localized is true while a built-in validation is using its default
+	// message, which may be replaced by a message from Form.Catalog.
+	localized bool
 }
 
 // ValidationError is returned whenever an error arises from a validation
@@ -23,7 +37,68 @@ type InputValidation struct {
 type ValidationError struct {
 	Input     *Input
 	InputName string
-	msg       string
+	// Code identifies the rule which the input violated, e.g. RuleRequired or
+	// RuleMaxLength. It is stable, so it can be used to tell different kinds
+	// of errors apart without comparing messages. Errors added directly with
+	// InputValidation.AddError have an empty Code.
+	Code string
+	// Params holds the parameters of the rule, e.g. Params{"limit": 20} for
+	// MaxLength(20). The keys for each rule are documented alongside the rule
+	// codes. Params is nil for rules without parameters.
+	Params Params
+	msg    string
+}
+
+// Params holds the parameters of a validation rule, keyed by name.
+type Params map[string]interface{}
+
+// The rule codes used for ValidationError.Code by the built-in validations. The
+// comment for each code lists the keys in ValidationError.Params.
+const (
+	RuleRequired       = "required"         // Required
+	RuleInteger        = "integer"          // IsInt, and any int validation if the input is not an int
+	RuleNumber         = "number"           // IsFloat, and any float validation if the input is not a number
+	RuleBool           = "bool"             // IsBool
+	RuleTime           = "time"             // IsTime, and any time validation if the input is not a time
+	RuleLess           = "less"             // Less and LessFloat: limit
+	RuleLessOrEqual    = "less_or_equal"    // LessOrEqual and LessOrEqualFloat: limit
+	RuleGreater        = "greater"          // Greater and GreaterFloat: limit
+	RuleGreaterOrEqual = "greater_or_equal" // GreaterOrEqual and GreaterOrEqualFloat: limit
+	RuleStep           = "step"             // StepInt and StepFloat: step, base
+	RuleMinLength      = "min_length"       // MinLength: limit
+	RuleMaxLength      = "max_length"       // MaxLength: limit
+	RuleLength         = "length"           // Length: length
+	RulePattern        = "pattern"          // Matches: pattern (a string)
+	RuleOneOf          = "one_of"           // OneOf: options
+	RuleNotOneOf       = "not_one_of"       // NotOneOf: options
+	RuleEmail          = "email"            // IsEmail
+	RuleURL            = "url"              // IsURL: schemes
+	RuleTel            = "tel"              // IsTel
+	RuleHexColor       = "hex_color"        // IsHexColor
+	RuleUUID           = "uuid"             // IsUUID
+	RuleBefore         = "before"           // Before: limit
+	RuleAfter          = "after"            // After: limit
+	RuleBetween        = "between"          // Between: start, end
+	RuleNotInFuture    = "not_in_future"    // NotInFuture
+	RuleNotInPast      = "not_in_past"      // NotInPast
+	RuleMin            = "min"              // ValidateConstraints for the min attribute: limit (a string)
+	RuleMax            = "max"              // ValidateConstraints for the max attribute: limit (a string)
+	RuleRange          = "range"            // ValidateConstraints for a time range which wraps around midnight: min, max (strings)
+	RuleEqualTo        = "equal_to"         // EqualTo: other
+	RuleGreaterThan    = "greater_than"     // GreaterThanField: other
+	RuleLessThan       = "less_than"        // LessThanField: other
+	RuleRequiredIf     = "required_if"      // RequiredIf: other, value
+	RuleRequiredUnless = "required_unless"  // RequiredUnless: other, value
+	RuleRequiredWith   = "required_with"    // RequiredWith: others
+	RuleWeek           = "week"             // Form.AddBindErrors for Week fields
+	RuleInvalid        = "invalid"          // Form.AddBindErrors for other fields which could not be bound
+)
+
+// rule identifies the built-in validation rule which is being checked, along
+// with its parameters.
+type rule struct {
+	code   string
+	params Params
 }
 
 // Error satisfies the Error method of the builtin error interface.
@@ -42,29 +117,85 @@ func (form *Form) Validate(inputName string) *InputValidation {
 }
 
 // AddError adds a validation error to the form with the given format and args.
-// The arguments format and args work exactly like they do in fmt.Sprintf.
+// The arguments format and args work exactly like they do in fmt.Sprintf. The
+// Code of the resulting ValidationError is empty.
 func (val *InputValidation) AddError(format string, args ...interface{}) {
+	val.addError(rule{}, format, args...)
+}
+
+// addError is like AddError but also records the rule which the input
+// violated. If the validation is using its default message and the form has a
+// Catalog with a template for the rule, the message from the template is used
+// instead of format and args.
+func (val *InputValidation) addError(r rule, format string, args ...interface{}) {
 	err := fmt.Errorf(format, args...)
+	if val.localized {
+		if msg, ok := val.Form.message(val.InputName, r); ok {
+			err = errors.New(msg)
+		}
+	}
 	val.Errors = append(val.Errors, err)
 	valErr := &ValidationError{
 		Input:     val.Input,
 		InputName: val.InputName,
+		Code:      r.code,
+		Params:    r.params,
 		msg:       err.Error(),
 	}
 	val.Form.Errors = append(val.Form.Errors, valErr)
 }
 
+// addDefaultError is like addError but always uses a default message, which
+// may be replaced by a message from the form's Catalog.
+func (val *InputValidation) addDefaultError(r rule, format string, args ...interface{}) {
+	defer val.localize()()
+	val.addError(r, format, args...)
+}
+
+// localize marks val as using the default message of a built-in validation
+// until the returned function is called. Each built-in validation without the
+// "f" suffix calls it like this:
+//
+//	defer val.localize()()
+func (val *InputValidation) localize() func() {
+	previous := val.localized
+	val.localized = true
+	return func() {
+		val.localized = previous
+	}
+}
+
+// label returns the label of the input, which is used in default messages (see
+// Form.Label).
+func (val *InputValidation) label() string {
+	return val.Form.Label(val.InputName)
+}
+
+// isEmpty returns true iff the input is not included in the form, or if its
+// value is empty or would not be submitted by the browser (see Form.Inputs).
+func (val *InputValidation) isEmpty() bool {
+	return val.Input == nil || val.Input.value() == ""
+}
+
 // Required adds a validation error to the form if the input is not included in
-// the form or if it is an empty string.
+// the form, if it is an empty string, or if the browser would not submit it
+// (e.g. none of the radio buttons in a group is checked).
 func (val *InputValidation) Required() *InputValidation {
-	return val.Requiredf("%s is required.", val.InputName)
+	defer val.localize()()
+	return val.Requiredf(defaultFormats[RuleRequired], val.label())
 }
 
 // Requiredf is like Required but allows you to specify a custom error message.
 // The arguments format and args work exactly like they do in fmt.Sprintf.
 func (val *InputValidation) Requiredf(format string, args ...interface{}) *InputValidation {
-	if val.Input == nil || val.Input.RawValue == "" {
-		val.AddError(format, args...)
+	return val.required(rule{code: RuleRequired}, format, args...)
+}
+
+// required adds a validation error for the given rule if the input is not
+// included in the form or if it is an empty string.
+func (val *InputValidation) required(r rule, format string, args ...interface{}) *InputValidation {
+	if val.isEmpty() {
+		val.addError(r, format, args...)
 	}
 	return val
 }
@@ -96,87 +227,116 @@ func greaterOrEqualFunc(limit int) func(int) bool {
 // Less adds a validation error to the form if the input is not less than limit.
 // Less only works for int values.
 func (val *InputValidation) Less(limit int) *InputValidation {
-	return val.Lessf(limit, "%s must be less than %d.", val.InputName, limit)
+	defer val.localize()()
+	return val.Lessf(limit, defaultFormats[RuleLess], val.label(), limit)
 }
 
 // Lessf is like Less but allows you to specify a custom error message.
 // The arguments format and args work exactly like they do in fmt.Sprintf.
 func (val *InputValidation) Lessf(limit int, format string, args ...interface{}) *InputValidation {
-	return val.validateInt(lessFunc(limit), format, args...)
+	return val.validateInt(rule{RuleLess, Params{"limit": limit}}, lessFunc(limit), format, args...)
 }
 
 // LessOrEqual adds a validation error to the form if the input is not less than
 // or equal to limit. LessOrEqual only works for int values.
 func (val *InputValidation) LessOrEqual(limit int) *InputValidation {
-	return val.LessOrEqualf(limit, "%s must be less than or equal to %d.", val.InputName, limit)
+	defer val.localize()()
+	return val.LessOrEqualf(limit, defaultFormats[RuleLessOrEqual], val.label(), limit)
 }
 
 // LessOrEqualf is like LessOrEqual but allows you to specify a custom error
 // message. The arguments format and args work exactly like they do in
 // fmt.Sprintf.
 func (val *InputValidation) LessOrEqualf(limit int, format string, args ...interface{}) *InputValidation {
-	return val.validateInt(lessOrEqualFunc(limit), format, args...)
+	return val.validateInt(rule{RuleLessOrEqual, Params{"limit": limit}}, lessOrEqualFunc(limit), format, args...)
 }
 
 // Greater adds a validation error to the form if the input is not greater than
 // limit. Greater only works for int values.
 func (val *InputValidation) Greater(limit int) *InputValidation {
-	return val.Greaterf(limit, "%s must be greater than %d.", val.InputName, limit)
+	defer val.localize()()
+	return val.Greaterf(limit, defaultFormats[RuleGreater], val.label(), limit)
 }
 
 // Greaterf is like Greater but allows you to specify a custom error message.
 // The arguments format and args work exactly like they do in fmt.Sprintf.
 func (val *InputValidation) Greaterf(limit int, format string, args ...interface{}) *InputValidation {
-	return val.validateInt(greaterFunc(limit), format, args...)
+	return val.validateInt(rule{RuleGreater, Params{"limit": limit}}, greaterFunc(limit), format, args...)
 }
 
 // GreaterOrEqual adds a validation error to the form if the input is not
 // greater than or equal to limit. GreaterOrEqual only works for int values.
 func (val *InputValidation) GreaterOrEqual(limit int) *InputValidation {
-	return val.GreaterOrEqualf(limit, "%s must be greater than or equal to %d.", val.InputName, limit)
+	defer val.localize()()
+	return val.GreaterOrEqualf(limit, defaultFormats[RuleGreaterOrEqual], val.label(), limit)
 }
 
 // GreaterOrEqualf is like GreaterOrEqual but allows you to specify a custom error message.
 // The arguments format and args work exactly like they do in fmt.Sprintf.
 func (val *InputValidation) GreaterOrEqualf(limit int, format string, args ...interface{}) *InputValidation {
-	return val.validateInt(greaterOrEqualFunc(limit), format, args...)
+	return val.validateInt(rule{RuleGreaterOrEqual, Params{"limit": limit}}, greaterOrEqualFunc(limit), format, args...)
+}
+
+// StepInt adds a validation error to the form if the input is not equal to base
+// plus an integer multiple of step, like the step attribute of a number input
+// (where base is the min attribute). For example, StepInt(6, 0) only allows
+// quantities in packs of 6. If step is not positive, StepInt does nothing.
+// StepInt only works for int values.
+func (val *InputValidation) StepInt(step, base int) *InputValidation {
+	defer val.localize()()
+	if base == 0 {
+		return val.StepIntf(step, base, defaultFormats[RuleStep], val.label(), step)
+	}
+	return val.StepIntf(step, base, defaultFormats[RuleStep+baseVariant], val.label(), base, step)
+}
+
+// StepIntf is like StepInt but allows you to specify a custom error message.
+// The arguments format and args work exactly like they do in fmt.Sprintf.
+func (val *InputValidation) StepIntf(step, base int, format string, args ...interface{}) *InputValidation {
+	if step <= 0 {
+		return val
+	}
+	return val.validateInt(rule{RuleStep, Params{"step": step, "base": base}}, func(value int) bool {
+		return (value-base)%step == 0
+	}, format, args...)
 }
 
 // IsInt adds a validation error to the form if the input is not convertible
 // to an int.
 func (val *InputValidation) IsInt() *InputValidation {
-	return val.IsIntf("%s must be an integer.", val.InputName)
+	defer val.localize()()
+	return val.IsIntf(defaultFormats[RuleInteger], val.label())
 }
 
 // IsIntf is like IsInt but allows you to specify a custom error message.
 // The arguments format and args work exactly like they do in fmt.Sprintf.
 func (val *InputValidation) IsIntf(format string, args ...interface{}) *InputValidation {
 	// If the input does not exist or is empty, skip this validation.
-	if val.Input == nil || val.Input.RawValue == "" {
+	if val.isEmpty() {
 		return val
 	}
 	// Attempt to convert the input value to a int and if the conversion fails,
 	// add a validation error.
 	if _, err := val.Input.Int(); err != nil {
-		val.AddError(format, args...)
+		val.addError(rule{code: RuleInteger}, format, args...)
 	}
 	return val
 }
 
-func (val *InputValidation) validateInt(validateFunc func(value int) bool, format string, args ...interface{}) *InputValidation {
+func (val *InputValidation) validateInt(r rule, validateFunc func(value int) bool, format string, args ...interface{}) *InputValidation {
 	// If the input does not exist or is empty, skip this validation.
-	if val.Input == nil || val.Input.RawValue == "" {
+	if val.isEmpty() {
 		return val
 	}
 	// Attempt to convert the input value to an integer.
 	intVal, err := val.Input.Int()
 	if err != nil {
-		val.AddError("%s must be an integer.", val.InputName)
+		val.addDefaultError(rule{code: RuleInteger}, defaultFormats[RuleInteger], val.label())
 		return val
 	}
 	// Call validateFunc and if it returns false, add the appropriate error.
 	if !validateFunc(intVal) {
-		val.AddError(format, args...)
+		val.addError(r, format, args...)
 	}
 	return val
 }
@@ -208,91 +368,146 @@ func greaterOrEqualFloatFunc(limit float64) func(float64) bool {
 // LessFloat adds a validation error to the form if the input is not less than
 // limit. LessFloat only works for float values.
 func (val *InputValidation) LessFloat(limit float64) *InputValidation {
-	return val.LessFloatf(limit, "%s must be less than %f.", val.InputName, limit)
+	defer val.localize()()
+	return val.LessFloatf(limit, defaultFormats[RuleLess+floatVariant], val.label(), limit)
 }
 
 // LessFloatf is like LessFloat but allows you to specify a custom error
 // message. The arguments format and args work exactly like they do in
 // fmt.Sprintf.
 func (val *InputValidation) LessFloatf(limit float64, format string, args ...interface{}) *InputValidation {
-	return val.validateFloat(lessFloatFunc(limit), format, args...)
+	return val.validateFloat(rule{RuleLess, Params{"limit": limit}}, lessFloatFunc(limit), format, args...)
 }
 
 // LessOrEqualFloat adds a validation error to the form if the input is not less
 // than or equal to limit. LessOrEqualFloat only works for float values.
 func (val *InputValidation) LessOrEqualFloat(limit float64) *InputValidation {
-	return val.LessOrEqualFloatf(limit, "%s must be less than or equal to %f.", val.InputName, limit)
+	defer val.localize()()
+	return val.LessOrEqualFloatf(limit, defaultFormats[RuleLessOrEqual+floatVariant], val.label(), limit)
 }
 
 // LessOrEqualFloatf is like LessOrEqualFloat but allows you to specify a custom
 // error message. The arguments format and args work exactly like they do in
 // fmt.Sprintf.
 func (val *InputValidation) LessOrEqualFloatf(limit float64, format string, args ...interface{}) *InputValidation {
-	return val.validateFloat(lessOrEqualFloatFunc(limit), format, args...)
+	return val.validateFloat(rule{RuleLessOrEqual, Params{"limit": limit}}, lessOrEqualFloatFunc(limit), format, args...)
 }
 
 // GreaterFloat adds a validation error to the form if the input is not greater
 // than limit. GreaterFloat only works for float values.
 func (val *InputValidation) GreaterFloat(limit float64) *InputValidation {
-	return val.GreaterFloatf(limit, "%s must be greater than %f.", val.InputName, limit)
+	defer val.localize()()
+	return val.GreaterFloatf(limit, defaultFormats[RuleGreater+floatVariant], val.label(), limit)
 }
 
 // GreaterFloatf is like GreaterFloat but allows you to specify a custom error
 // message. The arguments format and args work exactly like they do in
 // fmt.Sprintf.
 func (val *InputValidation) GreaterFloatf(limit float64, format string, args ...interface{}) *InputValidation {
-	return val.validateFloat(greaterFloatFunc(limit), format, args...)
+	return val.validateFloat(rule{RuleGreater, Params{"limit": limit}}, greaterFloatFunc(limit), format, args...)
 }
 
 // GreaterOrEqualFloat adds a validation error to the form if the input is not
 // greater than or equal to limit. GreaterOrEqualFloat only works for float
 // values.
 func (val *InputValidation) GreaterOrEqualFloat(limit float64) *InputValidation {
-	return val.GreaterOrEqualFloatf(limit, "%s must be greater than or equal to %f.", val.InputName, limit)
+	defer val.localize()()
+	return val.GreaterOrEqualFloatf(limit, defaultFormats[RuleGreaterOrEqual+floatVariant], val.label(), limit)
 }
 
 // GreaterOrEqualFloatf is like GreaterOrEqualFloat but allows you to specify a
 // custom error message. The arguments format and args work exactly like they do
 // in fmt.Sprintf.
 func (val *InputValidation) GreaterOrEqualFloatf(limit float64, format string, args ...interface{}) *InputValidation {
-	return val.validateFloat(greaterOrEqualFloatFunc(limit), format, args...)
+	return val.validateFloat(rule{RuleGreaterOrEqual, Params{"limit": limit}}, greaterOrEqualFloatFunc(limit), format, args...)
+}
+
+// StepFloat adds a validation error to the form if the input is not equal to
+// base plus an integer multiple of step, like the step attribute of a number
+// input (where base is the min attribute). For example, StepFloat(0.01, 0)
+// only allows prices with at most two decimal places. The comparison is done
+// with exact decimal arithmetic using the shortest representation of each
+// number, so e.g. 0.3 is a multiple of 0.1. If step is not positive, StepFloat
+// does nothing. StepFloat only works for float values.
+func (val *InputValidation) StepFloat(step, base float64) *InputValidation {
+	defer val.localize()()
+	if base == 0 {
+		return val.StepFloatf(step, base, defaultFormats[RuleStep], val.label(), step)
+	}
+	return val.StepFloatf(step, base, defaultFormats[RuleStep+baseVariant], val.label(), base, step)
+}
+
+// StepFloatf is like StepFloat but allows you to specify a custom error
+// message. The arguments format and args work exactly like they do in
+// fmt.Sprintf.
+func (val *InputValidation) StepFloatf(step, base float64, format string, args ...interface{}) *InputValidation {
+	if !(step > 0) {
+		return val
+	}
+	return val.validateFloat(rule{RuleStep, Params{"step": step, "base": base}}, func(value float64) bool {
+		return isStepMultiple(value, step, base)
+	}, format, args...)
+}
+
+// isStepMultiple returns true iff value is equal to base plus an integer
+// multiple of step. The numbers are converted to exact decimal fractions using
+// their shortest representation, so that e.g. 0.3 is a multiple of 0.1 even
+// though it is not when using floating point arithmetic. Infinite and NaN values
+// are never a multiple of step.
+func isStepMultiple(value, step, base float64) bool {
+	for _, f := range []float64{value, step, base} {
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return false
+		}
+	}
+	diff := new(big.Rat).Sub(exactDecimal(value), exactDecimal(base))
+	quotient := diff.Quo(diff, exactDecimal(step))
+	return quotient.IsInt()
+}
+
+// exactDecimal converts f to a big.Rat which is exactly equal to the shortest
+// decimal representation of f.
+func exactDecimal(f float64) *big.Rat {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	return r
 }
 
 // IsFloat adds a validation error to the form if the input is not convertible
 // to a float64.
 func (val *InputValidation) IsFloat() *InputValidation {
-	return val.IsFloatf("%s must be a number.", val.InputName)
+	defer val.localize()()
+	return val.IsFloatf(defaultFormats[RuleNumber], val.label())
 }
 
 // IsFloatf is like IsFloat but allows you to specify a custom error message.
 // The arguments format and args work exactly like they do in fmt.Sprintf.
 func (val *InputValidation) IsFloatf(format string, args ...interface{}) *InputValidation {
 	// If the input does not exist or is empty, skip this validation.
-	if val.Input == nil || val.Input.RawValue == "" {
+	if val.isEmpty() {
 		return val
 	}
 	// Attempt to convert the input value to a float and if the conversion fails,
 	// add a validation error.
 	if _, err := val.Input.Float(); err != nil {
-		val.AddError(format, args...)
+		val.addError(rule{code: RuleNumber}, format, args...)
 	}
 	return val
 }
 
-func (val *InputValidation) validateFloat(validateFunc func(value float64) bool, format string, args ...interface{}) *InputValidation {
+func (val *InputValidation) validateFloat(r rule, validateFunc func(value float64) bool, format string, args ...interface{}) *InputValidation {
 	// If the input does not exist or is empty, skip this validation.
-	if val.Input == nil || val.Input.RawValue == "" {
+	if val.isEmpty() {
 		return val
 	}
 	// Attempt to convert the input value to a float.
 	floatVal, err := val.Input.Float()
 	if err != nil {
-		val.AddError("%s must be a number.", val.InputName)
+		val.addDefaultError(rule{code: RuleNumber}, defaultFormats[RuleNumber], val.label())
 		return val
 	}
 	// Call validateFunc and if it returns false, add the appropriate error.
 	if !validateFunc(floatVal) {
-		val.AddError(format, args...)
+		val.addError(r, format, args...)
 	}
 	return val
 }
@@ -300,20 +515,459 @@ func (val *InputValidation) validateFloat(validateFunc func(value float64) bool,
 // IsBool adds a validation error to the form if the input is not convertible
 // to a bool.
 func (val *InputValidation) IsBool() *InputValidation {
-	return val.IsBoolf("%s must be either true or false.", val.InputName)
+	defer val.localize()()
+	return val.IsBoolf(defaultFormats[RuleBool], val.label())
 }
 
 // IsBoolf is like IsBool but allows you to specify a custom error message.
 // The arguments format and args work exactly like they do in fmt.Sprintf.
 func (val *InputValidation) IsBoolf(format string, args ...interface{}) *InputValidation {
 	// If the input does not exist or is empty, skip this validation.
-	if val.Input == nil || val.Input.RawValue == "" {
+	if val.isEmpty() {
 		return val
 	}
 	// Attempt to convert the input to a boolean and if the conversion fails,
 	// add a validation error.
 	if _, err := val.Input.Bool(); err != nil {
-		val.AddError(format, args...)
+		val.addError(rule{code: RuleBool}, format, args...)
+	}
+	return val
+}
+
+// validateString calls validateFunc with the value of the input and adds a
+// validation error for the rule r with the given format and args if it returns
+// false. If the input does not exist or is empty, validateString does nothing.
+func (val *InputValidation) validateString(r rule, validateFunc func(value string) bool, format string, args ...interface{}) *InputValidation {
+	// If the input does not exist or is empty, skip this validation.
+	if val.isEmpty() {
+		return val
+	}
+	// Call validateFunc and if it returns false, add the appropriate error.
+	if !validateFunc(val.Input.RawValue) {
+		val.addError(r, format, args...)
+	}
+	return val
+}
+
+// MinLength adds a validation error to the form if the input is shorter than
+// limit. Length is measured in characters (runes), not bytes.
+func (val *InputValidation) MinLength(limit int) *InputValidation {
+	defer val.localize()()
+	return val.MinLengthf(limit, defaultFormats[RuleMinLength], val.label(), limit)
+}
+
+// MinLengthf is like MinLength but allows you to specify a custom error
+// message. The arguments format and args work exactly like they do in
+// fmt.Sprintf.
+func (val *InputValidation) MinLengthf(limit int, format string, args ...interface{}) *InputValidation {
+	return val.validateString(rule{RuleMinLength, Params{"limit": limit}}, func(value string) bool {
+		return utf8.RuneCountInString(value) >= limit
+	}, format, args...)
+}
+
+// MaxLength adds a validation error to the form if the input is longer than
+// limit. Length is measured in characters (runes), not bytes.
+func (val *InputValidation) MaxLength(limit int) *InputValidation {
+	defer val.localize()()
+	return val.MaxLengthf(limit, defaultFormats[RuleMaxLength], val.label(), limit)
+}
+
+// MaxLengthf is like MaxLength but allows you to specify a custom error
+// message. The arguments format and args work exactly like they do in
+// fmt.Sprintf.
+func (val *InputValidation) MaxLengthf(limit int, format string, args ...interface{}) *InputValidation {
+	return val.validateString(rule{RuleMaxLength, Params{"limit": limit}}, func(value string) bool {
+		return utf8.RuneCountInString(value) <= limit
+	}, format, args...)
+}
+
+// Length adds a validation error to the form if the length of the input is not
+// exactly length. Length is measured in characters (runes), not bytes.
+func (val *InputValidation) Length(length int) *InputValidation {
+	defer val.localize()()
+	return val.Lengthf(length, defaultFormats[RuleLength], val.label(), length)
+}
+
+// Lengthf is like Length but allows you to specify a custom error message.
+// The arguments format and args work exactly like they do in fmt.Sprintf.
+func (val *InputValidation) Lengthf(length int, format string, args ...interface{}) *InputValidation {
+	return val.validateString(rule{RuleLength, Params{"length": length}}, func(value string) bool {
+		return utf8.RuneCountInString(value) == length
+	}, format, args...)
+}
+
+// Matches adds a validation error to the form if the input does not match
+// pattern. Note that, as with pattern.MatchString, the pattern may match any
+// part of the input unless it is anchored with ^ and $.
+func (val *InputValidation) Matches(pattern *regexp.Regexp) *InputValidation {
+	defer val.localize()()
+	return val.Matchesf(pattern, defaultFormats[RulePattern], val.label())
+}
+
+// Matchesf is like Matches but allows you to specify a custom error message.
+// The arguments format and args work exactly like they do in fmt.Sprintf.
+func (val *InputValidation) Matchesf(pattern *regexp.Regexp, format string, args ...interface{}) *InputValidation {
+	return val.validateString(rule{RulePattern, Params{"pattern": pattern.String()}}, pattern.MatchString, format, args...)
+}
+
+// OneOf adds a validation error to the form if the input is not equal to one
+// of options.
+func (val *InputValidation) OneOf(options []string) *InputValidation {
+	defer val.localize()()
+	return val.OneOfF(options, defaultFormats[RuleOneOf], val.label(), strings.Join(options, ", "))
+}
+
+// OneOfF is like OneOf but allows you to specify a custom error message.
+// The arguments format and args work exactly like they do in fmt.Sprintf.
+func (val *InputValidation) OneOfF(options []string, format string, args ...interface{}) *InputValidation {
+	return val.validateString(rule{RuleOneOf, Params{"options": options}}, func(value string) bool {
+		return containsString(options, value)
+	}, format, args...)
+}
+
+// NotOneOf adds a validation error to the form if the input is equal to one
+// of options.
+func (val *InputValidation) NotOneOf(options []string) *InputValidation {
+	defer val.localize()()
+	return val.NotOneOfF(options, defaultFormats[RuleNotOneOf], val.label(), strings.Join(options, ", "))
+}
+
+// NotOneOfF is like NotOneOf but allows you to specify a custom error message.
+// The arguments format and args work exactly like they do in fmt.Sprintf.
+func (val *InputValidation) NotOneOfF(options []string, format string, args ...interface{}) *InputValidation {
+	return val.validateString(rule{RuleNotOneOf, Params{"options": options}}, func(value string) bool {
+		return !containsString(options, value)
+	}, format, args...)
+}
+
+var (
+	// emailRegexp matches valid email addresses. It is the same expression
+	// that browsers use to validate inputs with the type email (see
+	// https://html.spec.whatwg.org/multipage/input.html#valid-e-mail-address).
+	emailRegexp = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
+	// telRegexp matches phone numbers consisting of digits and common
+	// separators, with an optional leading plus sign.
+	telRegexp = regexp.MustCompile(`^\+?[0-9()\-. ]+$`)
+	// hexColorRegexp matches hex colors in either the short (#f00) or long
+	// (#ff0000) form.
+	hexColorRegexp = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+	// uuidRegexp matches UUIDs in the canonical 8-4-4-4-12 form.
+	uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// IsEmail adds a validation error to the form if the input is not a valid
+// email address. It uses the same rules that browsers use for inputs with the
+// type email.
+func (val *InputValidation) IsEmail() *InputValidation {
+	defer val.localize()()
+	return val.IsEmailf(defaultFormats[RuleEmail], val.label())
+}
+
+// IsEmailf is like IsEmail but allows you to specify a custom error message.
+// The arguments format and args work exactly like they do in fmt.Sprintf.
+func (val *InputValidation) IsEmailf(format string, args ...interface{}) *InputValidation {
+	return val.validateString(rule{code: RuleEmail}, emailRegexp.MatchString, format, args...)
+}
+
+// IsURL adds a validation error to the form if the input is not an absolute
+// url. If any schemes are provided (e.g. "http" and "https"), the scheme of
+// the url must be one of them (ignoring case).
+func (val *InputValidation) IsURL(schemes ...string) *InputValidation {
+	defer val.localize()()
+	if len(schemes) == 0 {
+		return val.IsURLf(schemes, defaultFormats[RuleURL], val.label())
+	}
+	return val.IsURLf(schemes, defaultFormats[RuleURL+schemesVariant], val.label(), strings.Join(schemes, ", "))
+}
+
+// IsURLf is like IsURL but allows you to specify a custom error message.
+// The arguments format and args work exactly like they do in fmt.Sprintf.
+func (val *InputValidation) IsURLf(schemes []string, format string, args ...interface{}) *InputValidation {
+	return val.validateString(rule{RuleURL, Params{"schemes": schemes}}, func(value string) bool {
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "") {
+			return false
+		}
+		if len(schemes) == 0 {
+			return true
+		}
+		for _, scheme := range schemes {
+			if strings.EqualFold(u.Scheme, scheme) {
+				return true
+			}
+		}
+		return false
+	}, format, args...)
+}
+
+// IsTel adds a validation error to the form if the input is not a phone
+// number. A phone number may contain digits, spaces, parentheses, dots, and
+// dashes, may start with a plus sign, and must have between 3 and 15 digits.
+func (val *InputValidation) IsTel() *InputValidation {
+	defer val.localize()()
+	return val.IsTelf(defaultFormats[RuleTel], val.label())
+}
+
+// IsTelf is like IsTel but allows you to specify a custom error message.
+// The arguments format and args work exactly like they do in fmt.Sprintf.
+func (val *InputValidation) IsTelf(format string, args ...interface{}) *InputValidation {
+	return val.validateString(rule{code: RuleTel}, func(value string) bool {
+		if !telRegexp.MatchString(value) {
+			return false
+		}
+		digits := 0
+		for _, r := range value {
+			if r >= '0' && r <= '9' {
+				digits++
+			}
+		}
+		return digits >= 3 && digits <= 15
+	}, format, args...)
+}
+
+// IsHexColor adds a validation error to the form if the input is not a hex
+// color such as #f00 or #ff0000.
+func (val *InputValidation) IsHexColor() *InputValidation {
+	defer val.localize()()
+	return val.IsHexColorf(defaultFormats[RuleHexColor], val.label())
+}
+
+// IsHexColorf is like IsHexColor but allows you to specify a custom error
+// message. The arguments format and args work exactly like they do in
+// fmt.Sprintf.
+func (val *InputValidation) IsHexColorf(format string, args ...interface{}) *InputValidation {
+	return val.validateString(rule{code: RuleHexColor}, hexColorRegexp.MatchString, format, args...)
+}
+
+// IsUUID adds a validation error to the form if the input is not a UUID in the
+// canonical form, e.g. 123e4567-e89b-12d3-a456-426614174000.
+func (val *InputValidation) IsUUID() *InputValidation {
+	defer val.localize()()
+	return val.IsUUIDf(defaultFormats[RuleUUID], val.label())
+}
+
+// IsUUIDf is like IsUUID but allows you to specify a custom error message.
+// The arguments format and args work exactly like they do in fmt.Sprintf.
+func (val *InputValidation) IsUUIDf(format string, args ...interface{}) *InputValidation {
+	return val.validateString(rule{code: RuleUUID}, uuidRegexp.MatchString, format, args...)
+}
+
+// IsType adds a validation error to the form if the input is not valid for the
+// input's type attribute. It applies IsEmail for email inputs, IsURL for url
+// inputs, IsTel for tel inputs, IsHexColor for color inputs, IsFloat for
+// number and range inputs, and IsTime for date, datetime, datetime-local, month,
+// week, and time inputs. For all other types, IsType does nothing.
+func (val *InputValidation) IsType() *InputValidation {
+	if val.Input == nil {
+		return val
+	}
+	switch val.Input.Type {
+	case InputEmail:
+		return val.IsEmail()
+	case InputURL:
+		return val.IsURL()
+	case InputTel:
+		return val.IsTel()
+	case InputColor:
+		return val.IsHexColor()
+	case InputNumber, InputRange:
+		return val.IsFloat()
+	case InputDate, InputDateTime, InputDateTimeLocal, InputMonth, InputWeek, InputTime:
+		return val.IsTime()
+	}
+	return val
+}
+
+// IsTypef is like IsType but allows you to specify a custom error message.
+// The arguments format and args work exactly like they do in fmt.Sprintf.
+func (val *InputValidation) IsTypef(format string, args ...interface{}) *InputValidation {
+	if val.Input == nil {
+		return val
+	}
+	switch val.Input.Type {
+	case InputEmail:
+		return val.IsEmailf(format, args...)
+	case InputURL:
+		return val.IsURLf(nil, format, args...)
+	case InputTel:
+		return val.IsTelf(format, args...)
+	case InputColor:
+		return val.IsHexColorf(format, args...)
+	case InputNumber, InputRange:
+		return val.IsFloatf(format, args...)
+	case InputDate, InputDateTime, InputDateTimeLocal, InputMonth, InputWeek, InputTime:
+		return val.IsTimef(format, args...)
+	}
+	return val
+}
+
+// now returns the current time. It is a variable so that tests can override
+// it.
+var now = time.Now
+
+// currentTime returns the current time in the same frame of reference that
+// Input.Time uses for the given input type. Date, month, and week inputs are
+// parsed as midnight UTC on the first day of the date, month, or week, so the
+// current time is the first day of the current (local) date, month, or week at
+// midnight UTC. Local datetime and time inputs are parsed as a wall clock time
+// in UTC, so the current time is the local wall clock time in UTC (on January 1
+// of year 0 for time inputs). For all other types it returns the current time as
+// is.
+func currentTime(inputType InputType) time.Time {
+	t := now()
+	switch inputType {
+	case InputDate:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	case InputDateTimeLocal:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	case InputMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case InputWeek:
+		return WeekOf(t).Start()
+	case InputTime:
+		return ClockOf(t).On(time.Date(0, time.January, 1, 0, 0, 0, 0, time.UTC))
+	}
+	return t
+}
+
+// inputType returns the type of the input, or InputDefault if the input does
+// not exist.
+func (val *InputValidation) inputType() InputType {
+	if val.Input == nil {
+		return InputDefault
+	}
+	return val.Input.Type
+}
+
+// formatTimeLimit formats t for use in an error message, using the format
+// which corresponds to the type of the input.
+func (val *InputValidation) formatTimeLimit(t time.Time) string {
+	return formatTime(t, val.inputType())
+}
+
+// IsTime adds a validation error to the form if the input is not convertible
+// to a time.Time. See Input.Time for the supported formats.
+func (val *InputValidation) IsTime() *InputValidation {
+	defer val.localize()()
+	return val.IsTimef(defaultFormats[RuleTime], val.label())
+}
+
+// IsTimef is like IsTime but allows you to specify a custom error message.
+// The arguments format and args work exactly like they do in fmt.Sprintf.
+func (val *InputValidation) IsTimef(format string, args ...interface{}) *InputValidation {
+	// If the input does not exist or is empty, skip this validation.
+	if val.isEmpty() {
+		return val
+	}
+	// Attempt to convert the input value to a time and if the conversion fails,
+	// add a validation error.
+	if _, err := val.Input.Time(); err != nil {
+		val.addError(rule{code: RuleTime}, format, args...)
+	}
+	return val
+}
+
+func (val *InputValidation) validateTime(r rule, validateFunc func(value time.Time) bool, format string, args ...interface{}) *InputValidation {
+	// If the input does not exist or is empty, skip this validation.
+	if val.isEmpty() {
+		return val
+	}
+	// Attempt to convert the input value to a time.
+	timeVal, err := val.Input.Time()
+	if err != nil {
+		val.addDefaultError(rule{code: RuleTime}, defaultFormats[RuleTime], val.label())
+		return val
+	}
+	// Call validateFunc and if it returns false, add the appropriate error.
+	if !validateFunc(timeVal) {
+		val.addError(r, format, args...)
 	}
 	return val
 }
+
+// Before adds a validation error to the form if the input is not before limit.
+// Before only works for inputs which are convertible to a time.Time (see
+// Input.Time).
+func (val *InputValidation) Before(limit time.Time) *InputValidation {
+	defer val.localize()()
+	return val.Beforef(limit, defaultFormats[RuleBefore], val.label(), val.formatTimeLimit(limit))
+}
+
+// Beforef is like Before but allows you to specify a custom error message.
+// The arguments format and args work exactly like they do in fmt.Sprintf.
+func (val *InputValidation) Beforef(limit time.Time, format string, args ...interface{}) *InputValidation {
+	return val.validateTime(rule{RuleBefore, Params{"limit": limit}}, func(value time.Time) bool {
+		return value.Before(limit)
+	}, format, args...)
+}
+
+// After adds a validation error to the form if the input is not after limit.
+// After only works for inputs which are convertible to a time.Time (see
+// Input.Time).
+func (val *InputValidation) After(limit time.Time) *InputValidation {
+	defer val.localize()()
+	return val.Afterf(limit, defaultFormats[RuleAfter], val.label(), val.formatTimeLimit(limit))
+}
+
+// Afterf is like After but allows you to specify a custom error message.
+// The arguments format and args work exactly like they do in fmt.Sprintf.
+func (val *InputValidation) Afterf(limit time.Time, format string, args ...interface{}) *InputValidation {
+	return val.validateTime(rule{RuleAfter, Params{"limit": limit}}, func(value time.Time) bool {
+		return value.After(limit)
+	}, format, args...)
+}
+
+// Between adds a validation error to the form if the input is before start or
+// after end. The range is inclusive, so the input may be equal to start or
+// end. Between only works for inputs which are convertible to a time.Time (see
+// Input.Time).
+func (val *InputValidation) Between(start, end time.Time) *InputValidation {
+	defer val.localize()()
+	return val.Betweenf(start, end, defaultFormats[RuleBetween], val.label(), val.formatTimeLimit(start), val.formatTimeLimit(end))
+}
+
+// Betweenf is like Between but allows you to specify a custom error message.
+// The arguments format and args work exactly like they do in fmt.Sprintf.
+func (val *InputValidation) Betweenf(start, end time.Time, format string, args ...interface{}) *InputValidation {
+	return val.validateTime(rule{RuleBetween, Params{"start": start, "end": end}}, func(value time.Time) bool {
+		return !value.Before(start) && !value.After(end)
+	}, format, args...)
+}
+
+// NotInFuture adds a validation error to the form if the input is after the
+// current time. For date, month, and week inputs, only the date, month, or week
+// is compared, so the current date, month, or week is valid. For time inputs, only the time of day
+// is compared. NotInFuture only works for inputs which are convertible to a
+// time.Time (see Input.Time).
+func (val *InputValidation) NotInFuture() *InputValidation {
+	defer val.localize()()
+	return val.NotInFuturef(defaultFormats[RuleNotInFuture], val.label())
+}
+
+// NotInFuturef is like NotInFuture but allows you to specify a custom error
+// message. The arguments format and args work exactly like they do in
+// fmt.Sprintf.
+func (val *InputValidation) NotInFuturef(format string, args ...interface{}) *InputValidation {
+	return val.validateTime(rule{code: RuleNotInFuture}, func(value time.Time) bool {
+		return !value.After(currentTime(val.Input.Type))
+	}, format, args...)
+}
+
+// NotInPast adds a validation error to the form if the input is before the
+// current time. For date, month, and week inputs, only the date, month, or week
+// is compared, so the current date, month, or week is valid. For time inputs, only the time of day
+// is compared. NotInPast only works for inputs which are convertible to a
+// time.Time (see Input.Time).
+func (val *InputValidation) NotInPast() *InputValidation {
+	defer val.localize()()
+	return val.NotInPastf(defaultFormats[RuleNotInPast], val.label())
+}
+
+// NotInPastf is like NotInPast but allows you to specify a custom error
+// message. The arguments format and args work exactly like they do in
+// fmt.Sprintf.
+func (val *InputValidation) NotInPastf(format string, args ...interface{}) *InputValidation {
+	return val.validateTime(rule{code: RuleNotInPast}, func(value time.Time) bool {
+		return !value.Before(currentTime(val.Input.Type))
+	}, format, args...)
+}
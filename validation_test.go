@@ -0,0 +1,399 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// validationTestCase describes a single validation applied to an input named
+// "test". If input is nil, the form does not contain an input with that name.
+// expectedErrors holds the messages of all the errors that the validation is
+// expected to add to the form.
+type validationTestCase struct {
+	input          *Input
+	validate       func(val *InputValidation)
+	expectedErrors []string
+}
+
+// runValidationTestCases applies each validation in testCases to a new form
+// and checks the resulting errors.
+func runValidationTestCases(t *testing.T, testCases []validationTestCase) {
+	for i, tc := range testCases {
+		form := NewForm()
+		if tc.input != nil {
+			form.addInput(tc.input)
+		}
+		tc.validate(form.Validate("test"))
+		gotErrors := []string{}
+		for _, err := range form.Errors {
+			gotErrors = append(gotErrors, err.Error())
+		}
+		expectedErrors := tc.expectedErrors
+		if expectedErrors == nil {
+			expectedErrors = []string{}
+		}
+		if !reflect.DeepEqual(gotErrors, expectedErrors) {
+			t.Errorf("Test case %d: incorrect validation errors.\nExpected: %q\nBut got:  %q", i, expectedErrors, gotErrors)
+		}
+	}
+}
+
+// text returns an input named "test" with the given value and the type
+// InputText.
+func text(value string) *Input {
+	return newTestInput("test", InputText, value)
+}
+
+func TestValidateRequired(t *testing.T) {
+	runValidationTestCases(t, []validationTestCase{
+		{text("foo"), func(val *InputValidation) { val.Required() }, nil},
+		{text(""), func(val *InputValidation) { val.Required() }, []string{"test is required."}},
+		{text(""), func(val *InputValidation) { val.Requiredf("test cannot be blank.") }, []string{"test cannot be blank."}},
+		{nil, func(val *InputValidation) { val.Required() }, []string{"test is required."}},
+	})
+}
+
+func TestValidateInt(t *testing.T) {
+	runValidationTestCases(t, []validationTestCase{
+		// Less
+		{text("5"), func(val *InputValidation) { val.Less(10) }, nil},
+		{text("10"), func(val *InputValidation) { val.Less(10) }, []string{"test must be less than 10."}},
+		{text("10"), func(val *InputValidation) { val.Lessf(10, "custom") }, []string{"custom"}},
+		{text("foo"), func(val *InputValidation) { val.Less(10) }, []string{"test must be an integer."}},
+		{text(""), func(val *InputValidation) { val.Less(10) }, nil},
+		{nil, func(val *InputValidation) { val.Less(10) }, nil},
+		// LessOrEqual
+		{text("10"), func(val *InputValidation) { val.LessOrEqual(10) }, nil},
+		{text("11"), func(val *InputValidation) { val.LessOrEqual(10) }, []string{"test must be less than or equal to 10."}},
+		{text("11"), func(val *InputValidation) { val.LessOrEqualf(10, "custom") }, []string{"custom"}},
+		{text("foo"), func(val *InputValidation) { val.LessOrEqual(10) }, []string{"test must be an integer."}},
+		{nil, func(val *InputValidation) { val.LessOrEqual(10) }, nil},
+		// Greater
+		{text("15"), func(val *InputValidation) { val.Greater(10) }, nil},
+		{text("10"), func(val *InputValidation) { val.Greater(10) }, []string{"test must be greater than 10."}},
+		{text("10"), func(val *InputValidation) { val.Greaterf(10, "custom") }, []string{"custom"}},
+		{text("foo"), func(val *InputValidation) { val.Greater(10) }, []string{"test must be an integer."}},
+		{nil, func(val *InputValidation) { val.Greater(10) }, nil},
+		// GreaterOrEqual
+		{text("10"), func(val *InputValidation) { val.GreaterOrEqual(10) }, nil},
+		{text("9"), func(val *InputValidation) { val.GreaterOrEqual(10) }, []string{"test must be greater than or equal to 10."}},
+		{text("9"), func(val *InputValidation) { val.GreaterOrEqualf(10, "custom") }, []string{"custom"}},
+		{text("foo"), func(val *InputValidation) { val.GreaterOrEqual(10) }, []string{"test must be an integer."}},
+		{nil, func(val *InputValidation) { val.GreaterOrEqual(10) }, nil},
+		// IsInt
+		{text("5"), func(val *InputValidation) { val.IsInt() }, nil},
+		{text("foo"), func(val *InputValidation) { val.IsInt() }, []string{"test must be an integer."}},
+		{text("foo"), func(val *InputValidation) { val.IsIntf("custom") }, []string{"custom"}},
+		{nil, func(val *InputValidation) { val.IsInt() }, nil},
+		// StepInt
+		{text("12"), func(val *InputValidation) { val.StepInt(6, 0) }, nil},
+		{text("-6"), func(val *InputValidation) { val.StepInt(6, 0) }, nil},
+		{text("10"), func(val *InputValidation) { val.StepInt(6, 0) }, []string{"test must be a multiple of 6."}},
+		{text("13"), func(val *InputValidation) { val.StepInt(6, 1) }, nil},
+		{text("12"), func(val *InputValidation) { val.StepInt(6, 1) }, []string{"test must be 1 plus a multiple of 6."}},
+		{text("10"), func(val *InputValidation) { val.StepIntf(6, 0, "custom") }, []string{"custom"}},
+		{text("10"), func(val *InputValidation) { val.StepInt(0, 0) }, nil},
+		{text("foo"), func(val *InputValidation) { val.StepInt(6, 0) }, []string{"test must be an integer."}},
+		{nil, func(val *InputValidation) { val.StepInt(6, 0) }, nil},
+		// Chaining
+		{text("0"), func(val *InputValidation) { val.Required().IsInt().Greater(0).LessOrEqual(99) }, []string{"test must be greater than 0."}},
+	})
+}
+
+func TestValidateFloat(t *testing.T) {
+	runValidationTestCases(t, []validationTestCase{
+		// LessFloat
+		{text("5.0"), func(val *InputValidation) { val.LessFloat(10.0) }, nil},
+		{text("10.0"), func(val *InputValidation) { val.LessFloat(10.0) }, []string{"test must be less than 10.000000."}},
+		{text("10.0"), func(val *InputValidation) { val.LessFloatf(10.0, "custom") }, []string{"custom"}},
+		{text("foo"), func(val *InputValidation) { val.LessFloat(10.0) }, []string{"test must be a number."}},
+		{nil, func(val *InputValidation) { val.LessFloat(10.0) }, nil},
+		// LessOrEqualFloat
+		{text("10.0"), func(val *InputValidation) { val.LessOrEqualFloat(10.0) }, nil},
+		{text("10.1"), func(val *InputValidation) { val.LessOrEqualFloat(10.0) }, []string{"test must be less than or equal to 10.000000."}},
+		{text("10.1"), func(val *InputValidation) { val.LessOrEqualFloatf(10.0, "custom") }, []string{"custom"}},
+		{text("foo"), func(val *InputValidation) { val.LessOrEqualFloat(10.0) }, []string{"test must be a number."}},
+		{nil, func(val *InputValidation) { val.LessOrEqualFloat(10.0) }, nil},
+		// GreaterFloat
+		{text("10.1"), func(val *InputValidation) { val.GreaterFloat(10.0) }, nil},
+		{text("10.0"), func(val *InputValidation) { val.GreaterFloat(10.0) }, []string{"test must be greater than 10.000000."}},
+		{text("10.0"), func(val *InputValidation) { val.GreaterFloatf(10.0, "custom") }, []string{"custom"}},
+		{text("foo"), func(val *InputValidation) { val.GreaterFloat(10.0) }, []string{"test must be a number."}},
+		{nil, func(val *InputValidation) { val.GreaterFloat(10.0) }, nil},
+		// GreaterOrEqualFloat
+		{text("10.0"), func(val *InputValidation) { val.GreaterOrEqualFloat(10.0) }, nil},
+		{text("9.9"), func(val *InputValidation) { val.GreaterOrEqualFloat(10.0) }, []string{"test must be greater than or equal to 10.000000."}},
+		{text("9.9"), func(val *InputValidation) { val.GreaterOrEqualFloatf(10.0, "custom") }, []string{"custom"}},
+		{text("foo"), func(val *InputValidation) { val.GreaterOrEqualFloat(10.0) }, []string{"test must be a number."}},
+		{nil, func(val *InputValidation) { val.GreaterOrEqualFloat(10.0) }, nil},
+		// IsFloat
+		{text("5.3"), func(val *InputValidation) { val.IsFloat() }, nil},
+		{text("foo"), func(val *InputValidation) { val.IsFloat() }, []string{"test must be a number."}},
+		{text("foo"), func(val *InputValidation) { val.IsFloatf("custom") }, []string{"custom"}},
+		{nil, func(val *InputValidation) { val.IsFloat() }, nil},
+		// StepFloat
+		{text("19.99"), func(val *InputValidation) { val.StepFloat(0.01, 0) }, nil},
+		{text("0.3"), func(val *InputValidation) { val.StepFloat(0.1, 0) }, nil},
+		{text("19.995"), func(val *InputValidation) { val.StepFloat(0.01, 0) }, []string{"test must be a multiple of 0.01."}},
+		{text("1.2"), func(val *InputValidation) { val.StepFloat(0.5, 0.2) }, nil},
+		{text("1.0"), func(val *InputValidation) { val.StepFloat(0.5, 0.2) }, []string{"test must be 0.2 plus a multiple of 0.5."}},
+		{text("1.0"), func(val *InputValidation) { val.StepFloatf(0.5, 0.2, "custom") }, []string{"custom"}},
+		{text("Inf"), func(val *InputValidation) { val.StepFloat(0.5, 0) }, []string{"test must be a multiple of 0.5."}},
+		{text("1.0"), func(val *InputValidation) { val.StepFloat(-1, 0) }, nil},
+		{text("foo"), func(val *InputValidation) { val.StepFloat(0.5, 0) }, []string{"test must be a number."}},
+		{nil, func(val *InputValidation) { val.StepFloat(0.5, 0) }, nil},
+	})
+}
+
+func TestIsStepMultiple(t *testing.T) {
+	testCases := []struct {
+		value, step, base float64
+		expected          bool
+	}{
+		{0.3, 0.1, 0, true},
+		{1.15, 0.05, 0, true},
+		{19.99, 0.01, 0, true},
+		{19.995, 0.01, 0, false},
+		{12, 6, 0, true},
+		{13, 6, 1, true},
+		{-5, 5, 0, true},
+		{1e300, 3, 0, false},
+	}
+	for _, tc := range testCases {
+		if got := isStepMultiple(tc.value, tc.step, tc.base); got != tc.expected {
+			t.Errorf("isStepMultiple(%v, %v, %v): expected %v but got %v", tc.value, tc.step, tc.base, tc.expected, got)
+		}
+	}
+}
+
+func TestValidateIsBool(t *testing.T) {
+	runValidationTestCases(t, []validationTestCase{
+		{text("true"), func(val *InputValidation) { val.IsBool() }, nil},
+		{newCheckedTestInput("test", InputCheckbox, "on", true), func(val *InputValidation) { val.IsBool() }, nil},
+		{text("foo"), func(val *InputValidation) { val.IsBool() }, []string{"test must be either true or false."}},
+		{text("foo"), func(val *InputValidation) { val.IsBoolf("custom") }, []string{"custom"}},
+		{nil, func(val *InputValidation) { val.IsBool() }, nil},
+	})
+}
+
+func TestValidateStrings(t *testing.T) {
+	colors := []string{"red", "green"}
+	zip := regexp.MustCompile(`^\d{5}$`)
+	runValidationTestCases(t, []validationTestCase{
+		// MinLength
+		{text("foo"), func(val *InputValidation) { val.MinLength(3) }, nil},
+		{text("fo"), func(val *InputValidation) { val.MinLength(3) }, []string{"test must be at least 3 characters long."}},
+		{text("fo"), func(val *InputValidation) { val.MinLengthf(3, "custom") }, []string{"custom"}},
+		{text("日本語"), func(val *InputValidation) { val.MinLength(3) }, nil},
+		{text(""), func(val *InputValidation) { val.MinLength(3) }, nil},
+		{nil, func(val *InputValidation) { val.MinLength(3) }, nil},
+		// MaxLength
+		{text("foo"), func(val *InputValidation) { val.MaxLength(3) }, nil},
+		{text("日本語"), func(val *InputValidation) { val.MaxLength(3) }, nil},
+		{text("food"), func(val *InputValidation) { val.MaxLength(3) }, []string{"test must be at most 3 characters long."}},
+		{text("food"), func(val *InputValidation) { val.MaxLengthf(3, "custom") }, []string{"custom"}},
+		{nil, func(val *InputValidation) { val.MaxLength(3) }, nil},
+		// Length
+		{text("日本語"), func(val *InputValidation) { val.Length(3) }, nil},
+		{text("food"), func(val *InputValidation) { val.Length(3) }, []string{"test must be exactly 3 characters long."}},
+		{text("fo"), func(val *InputValidation) { val.Lengthf(3, "custom") }, []string{"custom"}},
+		{nil, func(val *InputValidation) { val.Length(3) }, nil},
+		// Matches
+		{text("12345"), func(val *InputValidation) { val.Matches(zip) }, nil},
+		{text("1234a"), func(val *InputValidation) { val.Matches(zip) }, []string{"test is not in the correct format."}},
+		{text("1234a"), func(val *InputValidation) { val.Matchesf(zip, "custom") }, []string{"custom"}},
+		{nil, func(val *InputValidation) { val.Matches(zip) }, nil},
+		// OneOf
+		{text("red"), func(val *InputValidation) { val.OneOf(colors) }, nil},
+		{text("blue"), func(val *InputValidation) { val.OneOf(colors) }, []string{"test must be one of: red, green."}},
+		{text("blue"), func(val *InputValidation) { val.OneOfF(colors, "custom") }, []string{"custom"}},
+		{nil, func(val *InputValidation) { val.OneOf(colors) }, nil},
+		// NotOneOf
+		{text("blue"), func(val *InputValidation) { val.NotOneOf(colors) }, nil},
+		{text("red"), func(val *InputValidation) { val.NotOneOf(colors) }, []string{"test must not be one of: red, green."}},
+		{text("red"), func(val *InputValidation) { val.NotOneOfF(colors, "custom") }, []string{"custom"}},
+		{nil, func(val *InputValidation) { val.NotOneOf(colors) }, nil},
+		// Chaining
+		{text("x"), func(val *InputValidation) { val.Required().MinLength(2).MaxLength(5) }, []string{"test must be at least 2 characters long."}},
+	})
+}
+
+func TestValidateFormats(t *testing.T) {
+	runValidationTestCases(t, []validationTestCase{
+		// IsEmail
+		{text("foo@example.com"), func(val *InputValidation) { val.IsEmail() }, nil},
+		{text("foo.bar+baz@sub.example.co"), func(val *InputValidation) { val.IsEmail() }, nil},
+		{text("foo@"), func(val *InputValidation) { val.IsEmail() }, []string{"test must be a valid email address."}},
+		{text("foo bar@example.com"), func(val *InputValidation) { val.IsEmail() }, []string{"test must be a valid email address."}},
+		{text("foo"), func(val *InputValidation) { val.IsEmailf("custom") }, []string{"custom"}},
+		{nil, func(val *InputValidation) { val.IsEmail() }, nil},
+		// IsURL
+		{text("http://example.com/foo"), func(val *InputValidation) { val.IsURL() }, nil},
+		{text("mailto:foo@example.com"), func(val *InputValidation) { val.IsURL() }, nil},
+		{text("example.com"), func(val *InputValidation) { val.IsURL() }, []string{"test must be a valid URL."}},
+		{text("/relative/path"), func(val *InputValidation) { val.IsURL() }, []string{"test must be a valid URL."}},
+		{text("HTTPS://example.com"), func(val *InputValidation) { val.IsURL("http", "https") }, nil},
+		{text("ftp://example.com"), func(val *InputValidation) { val.IsURL("http", "https") }, []string{"test must be a valid URL starting with one of: http, https."}},
+		{text("ftp://example.com"), func(val *InputValidation) { val.IsURLf([]string{"http"}, "custom") }, []string{"custom"}},
+		{nil, func(val *InputValidation) { val.IsURL() }, nil},
+		// IsTel
+		{text("867-5309"), func(val *InputValidation) { val.IsTel() }, nil},
+		{text("+1 (555) 867.5309"), func(val *InputValidation) { val.IsTel() }, nil},
+		{text("12"), func(val *InputValidation) { val.IsTel() }, []string{"test must be a valid phone number."}},
+		{text("555-CALL-NOW"), func(val *InputValidation) { val.IsTel() }, []string{"test must be a valid phone number."}},
+		{text("1234567890123456"), func(val *InputValidation) { val.IsTelf("custom") }, []string{"custom"}},
+		{nil, func(val *InputValidation) { val.IsTel() }, nil},
+		// IsHexColor
+		{text("#ff0000"), func(val *InputValidation) { val.IsHexColor() }, nil},
+		{text("#F00"), func(val *InputValidation) { val.IsHexColor() }, nil},
+		{text("red"), func(val *InputValidation) { val.IsHexColor() }, []string{"test must be a color in the format #rrggbb."}},
+		{text("#ff00"), func(val *InputValidation) { val.IsHexColorf("custom") }, []string{"custom"}},
+		{nil, func(val *InputValidation) { val.IsHexColor() }, nil},
+		// IsUUID
+		{text("123e4567-e89b-12d3-a456-426614174000"), func(val *InputValidation) { val.IsUUID() }, nil},
+		{text("123e4567e89b12d3a456426614174000"), func(val *InputValidation) { val.IsUUID() }, []string{"test must be a valid UUID."}},
+		{text("foo"), func(val *InputValidation) { val.IsUUIDf("custom") }, []string{"custom"}},
+		{nil, func(val *InputValidation) { val.IsUUID() }, nil},
+		// IsType
+		{newTestInput("test", InputEmail, "foo@example.com"), func(val *InputValidation) { val.IsType() }, nil},
+		{newTestInput("test", InputEmail, "foo"), func(val *InputValidation) { val.IsType() }, []string{"test must be a valid email address."}},
+		{newTestInput("test", InputURL, "foo"), func(val *InputValidation) { val.IsType() }, []string{"test must be a valid URL."}},
+		{newTestInput("test", InputTel, "x"), func(val *InputValidation) { val.IsType() }, []string{"test must be a valid phone number."}},
+		{newTestInput("test", InputColor, "x"), func(val *InputValidation) { val.IsType() }, []string{"test must be a color in the format #rrggbb."}},
+		{newTestInput("test", InputNumber, "x"), func(val *InputValidation) { val.IsType() }, []string{"test must be a number."}},
+		{newTestInput("test", InputNumber, "x"), func(val *InputValidation) { val.IsTypef("custom") }, []string{"custom"}},
+		{text("anything"), func(val *InputValidation) { val.IsType() }, nil},
+		{nil, func(val *InputValidation) { val.IsType() }, nil},
+	})
+}
+
+func TestValidateTime(t *testing.T) {
+	// Freeze the current time so that NotInFuture and NotInPast are
+	// deterministic.
+	defer func(original func() time.Time) { now = original }(now)
+	now = func() time.Time {
+		return time.Date(2015, 6, 15, 12, 0, 0, 0, time.UTC)
+	}
+	date := func(value string) *Input {
+		return newTestInput("test", InputDate, value)
+	}
+	start := mustParseTime("2006-01-02", "2015-06-01")
+	end := mustParseTime("2006-01-02", "2015-06-30")
+	runValidationTestCases(t, []validationTestCase{
+		// IsTime
+		{date("2015-06-01"), func(val *InputValidation) { val.IsTime() }, nil},
+		{date("2015-13-01"), func(val *InputValidation) { val.IsTime() }, []string{"test must be a valid time."}},
+		{date("foo"), func(val *InputValidation) { val.IsTimef("custom") }, []string{"custom"}},
+		{nil, func(val *InputValidation) { val.IsTime() }, nil},
+		// Before
+		{date("2015-05-31"), func(val *InputValidation) { val.Before(start) }, nil},
+		{date("2015-06-01"), func(val *InputValidation) { val.Before(start) }, []string{"test must be before 2015-06-01."}},
+		{date("2015-06-01"), func(val *InputValidation) { val.Beforef(start, "custom") }, []string{"custom"}},
+		{date("foo"), func(val *InputValidation) { val.Before(start) }, []string{"test must be a valid time."}},
+		{nil, func(val *InputValidation) { val.Before(start) }, nil},
+		// After
+		{date("2015-06-02"), func(val *InputValidation) { val.After(start) }, nil},
+		{date("2015-06-01"), func(val *InputValidation) { val.After(start) }, []string{"test must be after 2015-06-01."}},
+		{date("2015-06-01"), func(val *InputValidation) { val.Afterf(start, "custom") }, []string{"custom"}},
+		{nil, func(val *InputValidation) { val.After(start) }, nil},
+		// Between
+		{date("2015-06-01"), func(val *InputValidation) { val.Between(start, end) }, nil},
+		{date("2015-06-30"), func(val *InputValidation) { val.Between(start, end) }, nil},
+		{date("2015-07-01"), func(val *InputValidation) { val.Between(start, end) }, []string{"test must be between 2015-06-01 and 2015-06-30."}},
+		{date("2015-05-31"), func(val *InputValidation) { val.Betweenf(start, end, "custom") }, []string{"custom"}},
+		{nil, func(val *InputValidation) { val.Between(start, end) }, nil},
+		// NotInFuture
+		{date("2015-06-15"), func(val *InputValidation) { val.NotInFuture() }, nil},
+		{date("2015-06-16"), func(val *InputValidation) { val.NotInFuture() }, []string{"test must not be in the future."}},
+		{newTestInput("test", InputDateTime, "2015-06-15T13:00:00Z"), func(val *InputValidation) { val.NotInFuture() }, []string{"test must not be in the future."}},
+		{date("2015-06-16"), func(val *InputValidation) { val.NotInFuturef("custom") }, []string{"custom"}},
+		{nil, func(val *InputValidation) { val.NotInFuture() }, nil},
+		// NotInPast
+		{date("2015-06-15"), func(val *InputValidation) { val.NotInPast() }, nil},
+		{date("2015-06-14"), func(val *InputValidation) { val.NotInPast() }, []string{"test must not be in the past."}},
+		{newTestInput("test", InputDateTime, "2015-06-15T11:00:00Z"), func(val *InputValidation) { val.NotInPast() }, []string{"test must not be in the past."}},
+		{date("2015-06-14"), func(val *InputValidation) { val.NotInPastf("custom") }, []string{"custom"}},
+		{nil, func(val *InputValidation) { val.NotInPast() }, nil},
+		// IsType
+		{date("foo"), func(val *InputValidation) { val.IsType() }, []string{"test must be a valid time."}},
+		// Chaining with other validations
+		{date("2015-06-20"), func(val *InputValidation) { val.Required().IsTime().NotInFuture() }, []string{"test must not be in the future."}},
+	})
+}
+
+func TestValidationErrorCodes(t *testing.T) {
+	start := mustParseTime("2006-01-02", "2015-06-01")
+	end := mustParseTime("2006-01-02", "2015-06-30")
+	testCases := []struct {
+		inputs         []*Input
+		validate       func(val *InputValidation)
+		expectedCode   string
+		expectedParams Params
+	}{
+		{nil, func(val *InputValidation) { val.Required() }, RuleRequired, nil},
+		{nil, func(val *InputValidation) { val.Requiredf("custom") }, RuleRequired, nil},
+		{[]*Input{text("foo")}, func(val *InputValidation) { val.IsInt() }, RuleInteger, nil},
+		{[]*Input{text("foo")}, func(val *InputValidation) { val.Less(5) }, RuleInteger, nil},
+		{[]*Input{text("5")}, func(val *InputValidation) { val.Less(5) }, RuleLess, Params{"limit": 5}},
+		{[]*Input{text("5")}, func(val *InputValidation) { val.LessOrEqual(4) }, RuleLessOrEqual, Params{"limit": 4}},
+		{[]*Input{text("5")}, func(val *InputValidation) { val.Greater(5) }, RuleGreater, Params{"limit": 5}},
+		{[]*Input{text("5")}, func(val *InputValidation) { val.GreaterOrEqualf(6, "custom") }, RuleGreaterOrEqual, Params{"limit": 6}},
+		{[]*Input{text("5")}, func(val *InputValidation) { val.StepInt(2, 0) }, RuleStep, Params{"step": 2, "base": 0}},
+		{[]*Input{text("foo")}, func(val *InputValidation) { val.IsFloat() }, RuleNumber, nil},
+		{[]*Input{text("5.5")}, func(val *InputValidation) { val.LessFloat(5) }, RuleLess, Params{"limit": 5.0}},
+		{[]*Input{text("5.6")}, func(val *InputValidation) { val.StepFloat(0.2, 0.1) }, RuleStep, Params{"step": 0.2, "base": 0.1}},
+		{[]*Input{text("foo")}, func(val *InputValidation) { val.IsBool() }, RuleBool, nil},
+		{[]*Input{text("foo")}, func(val *InputValidation) { val.MinLength(5) }, RuleMinLength, Params{"limit": 5}},
+		{[]*Input{text("foo")}, func(val *InputValidation) { val.MaxLength(2) }, RuleMaxLength, Params{"limit": 2}},
+		{[]*Input{text("foo")}, func(val *InputValidation) { val.Length(2) }, RuleLength, Params{"length": 2}},
+		{[]*Input{text("foo")}, func(val *InputValidation) { val.Matches(regexp.MustCompile("^[0-9]+$")) }, RulePattern, Params{"pattern": "^[0-9]+$"}},
+		{[]*Input{text("foo")}, func(val *InputValidation) { val.OneOf([]string{"a"}) }, RuleOneOf, Params{"options": []string{"a"}}},
+		{[]*Input{text("foo")}, func(val *InputValidation) { val.NotOneOf([]string{"foo"}) }, RuleNotOneOf, Params{"options": []string{"foo"}}},
+		{[]*Input{text("foo")}, func(val *InputValidation) { val.IsEmail() }, RuleEmail, nil},
+		{[]*Input{text("foo")}, func(val *InputValidation) { val.IsURL("https") }, RuleURL, Params{"schemes": []string{"https"}}},
+		{[]*Input{text("foo")}, func(val *InputValidation) { val.IsTel() }, RuleTel, nil},
+		{[]*Input{text("foo")}, func(val *InputValidation) { val.IsHexColor() }, RuleHexColor, nil},
+		{[]*Input{text("foo")}, func(val *InputValidation) { val.IsUUID() }, RuleUUID, nil},
+		{[]*Input{newTestInput("test", InputEmail, "foo")}, func(val *InputValidation) { val.IsType() }, RuleEmail, nil},
+		{[]*Input{text("foo")}, func(val *InputValidation) { val.IsTime() }, RuleTime, nil},
+		{[]*Input{newTestInput("test", InputDate, "2015-07-01")}, func(val *InputValidation) { val.Before(start) }, RuleBefore, Params{"limit": start}},
+		{[]*Input{newTestInput("test", InputDate, "2015-05-01")}, func(val *InputValidation) { val.After(start) }, RuleAfter, Params{"limit": start}},
+		{[]*Input{newTestInput("test", InputDate, "2015-07-01")}, func(val *InputValidation) { val.Between(start, end) }, RuleBetween, Params{"start": start, "end": end}},
+		{[]*Input{newTestInput("test", InputDate, "9999-01-01")}, func(val *InputValidation) { val.NotInFuture() }, RuleNotInFuture, nil},
+		{[]*Input{newTestInput("test", InputDate, "1999-01-01")}, func(val *InputValidation) { val.NotInPast() }, RuleNotInPast, nil},
+		{[]*Input{text("foo")}, func(val *InputValidation) { val.EqualTo("other") }, RuleEqualTo, Params{"other": "other"}},
+		{[]*Input{text("1"), newTestInput("other", InputNumber, "2")}, func(val *InputValidation) { val.GreaterThanField("other") }, RuleGreaterThan, Params{"other": "other"}},
+		{[]*Input{text("3"), newTestInput("other", InputNumber, "2")}, func(val *InputValidation) { val.LessThanField("other") }, RuleLessThan, Params{"other": "other"}},
+		{[]*Input{newTestInput("other", InputText, "a")}, func(val *InputValidation) { val.RequiredIf("other", "a") }, RuleRequiredIf, Params{"other": "other", "value": "a"}},
+		{nil, func(val *InputValidation) { val.RequiredUnless("other", "a") }, RuleRequiredUnless, Params{"other": "other", "value": "a"}},
+		{[]*Input{newTestInput("other", InputText, "a")}, func(val *InputValidation) { val.RequiredWith("other") }, RuleRequiredWith, Params{"others": []string{"other"}}},
+	}
+	for i, tc := range testCases {
+		form := NewForm(tc.inputs...)
+		tc.validate(form.Validate("test"))
+		if len(form.Errors) != 1 {
+			t.Errorf("Test case %d: expected exactly one error but got %v", i, form.Errors)
+			continue
+		}
+		valErr := form.Errors[0].(*ValidationError)
+		if valErr.Code != tc.expectedCode {
+			t.Errorf("Test case %d: expected code %q but got %q", i, tc.expectedCode, valErr.Code)
+		}
+		if !reflect.DeepEqual(valErr.Params, tc.expectedParams) {
+			t.Errorf("Test case %d: expected params %v but got %v", i, tc.expectedParams, valErr.Params)
+		}
+	}
+	// Errors added with AddError have no code.
+	form := NewForm()
+	form.Validate("test").AddError("custom")
+	if valErr := form.Errors[0].(*ValidationError); valErr.Code != "" || valErr.Params != nil {
+		t.Errorf("Expected no code or params for a custom error but got %q and %v", valErr.Code, valErr.Params)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+//go:build js
+// +build js
+
+package form
+
+import (
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// SetCustomValidity pushes the validation errors in the form to the browser's
+// Constraint Validation API. It calls setCustomValidity on the element of each
+// input with the message of the first ValidationError for the input, or with
+// an empty string if the input has no errors. This makes the :invalid css
+// pseudo-class match exactly the inputs with errors, and lets ReportValidity
+// show the messages in the browser's native bubbles. Only inputs whose element
+// is a *DOMElement (i.e. forms created by Parse) are affected.
+//
+// Because a custom validity message makes an element invalid until it is
+// cleared, call SetCustomValidity again after validating the form again.
+func (form *Form) SetCustomValidity() {
+	errs := form.ErrorsByInput()
+	for name, inputs := range form.Groups {
+		message := ""
+		if len(errs[name]) > 0 {
+			message = errs[name][0].Error()
+		}
+		for _, el := range domElements(inputs) {
+			el.Underlying().Call("setCustomValidity", message)
+		}
+	}
+}
+
+// CheckValidity calls checkValidity on the element of every input in the form
+// and returns true iff all of them are valid according to the browser. Like
+// the checkValidity method of an html form element, it fires an invalid event
+// at each element which is not valid.
+func (form *Form) CheckValidity() bool {
+	valid := true
+	for _, inputs := range form.Groups {
+		for _, el := range domElements(inputs) {
+			if !el.Underlying().Call("checkValidity").Bool() {
+				valid = false
+			}
+		}
+	}
+	return valid
+}
+
+// ReportValidity is like CheckValidity, but also asks the browser to report
+// the problems to the user, usually by focusing the first invalid input and
+// showing its message in a bubble. If the inputs belong to an html form
+// element, ReportValidity calls reportValidity on the form element so that
+// the first invalid input in document order is reported.
+func (form *Form) ReportValidity() bool {
+	if formEl := form.formElement(); formEl != nil {
+		return formEl.Call("reportValidity").Bool()
+	}
+	valid := true
+	for _, name := range sortedInputNames(form.Inputs) {
+		for _, el := range domElements(form.Groups[name]) {
+			if valid && !el.Underlying().Call("reportValidity").Bool() {
+				valid = false
+			}
+		}
+	}
+	return valid
+}
+
+// ImportValidity adds a ValidationError to the form for each constraint which
+// the browser reports as violated in the ValidityState of the inputs'
+// elements. Each flag is converted to the rule with the same code that
+// ValidateConstraints would use:
+//
+//	valueMissing    RuleRequired
+//	badInput        RuleNumber for number and range inputs, RuleTime for date
+//	                and time inputs, and RuleInvalid otherwise
+//	typeMismatch    RuleEmail for email inputs, RuleURL for url inputs, and
+//	                RuleInvalid otherwise
+//	tooShort        RuleMinLength
+//	tooLong         RuleMaxLength
+//	patternMismatch RulePattern
+//	rangeUnderflow  RuleMin
+//	rangeOverflow   RuleMax, or RuleRange if the value is also below min
+//	stepMismatch    RuleStep
+//
+// The browser's validationMessage for an element only describes one of the
+// violated constraints, so it is used as the message of the first error for
+// the element, and the other errors get the default message for their rule,
+// e.g. the message of MinLength for RuleMinLength. In both cases, a template
+// for the rule in the form's Catalog takes precedence. A problem which is
+// reported by several elements with the same name, e.g. a group of required
+// radio buttons, is only added once. Custom validity messages (see
+// SetCustomValidity) are ignored and left in place.
+func (form *Form) ImportValidity() {
+	for _, name := range sortedInputNames(form.Inputs) {
+		reported := map[string]bool{}
+		for _, input := range form.Groups[name] {
+			el, ok := input.El.(*DOMElement)
+			if !ok {
+				continue
+			}
+			state, message := elementValidity(el)
+			val := &InputValidation{
+				Input:     input,
+				Form:      form,
+				InputName: name,
+			}
+			for i, r := range validityRules(input, state) {
+				if reported[r.code] {
+					continue
+				}
+				reported[r.code] = true
+				if i == 0 {
+					val.addDefaultError(r, "%s", message)
+				} else {
+					val.addDefaultError(r, "%s", val.validityMessage(r))
+				}
+			}
+		}
+	}
+}
+
+// elementValidity returns the ValidityState flags and the validationMessage of
+// el, ignoring any custom validity message.
+func elementValidity(el *DOMElement) (validityState, string) {
+	obj := el.Underlying()
+	validity := obj.Get("validity")
+	if validity == nil || validity == js.Undefined {
+		return validityState{}, ""
+	}
+	// Temporarily clear the custom validity message, so that validationMessage
+	// describes the violated constraint instead.
+	if validity.Get("customError").Bool() {
+		customMessage := obj.Get("validationMessage").String()
+		obj.Call("setCustomValidity", "")
+		defer obj.Call("setCustomValidity", customMessage)
+	}
+	state := validityState{
+		valueMissing:    validity.Get("valueMissing").Bool(),
+		typeMismatch:    validity.Get("typeMismatch").Bool(),
+		patternMismatch: validity.Get("patternMismatch").Bool(),
+		tooLong:         validity.Get("tooLong").Bool(),
+		tooShort:        validity.Get("tooShort").Bool(),
+		rangeUnderflow:  validity.Get("rangeUnderflow").Bool(),
+		rangeOverflow:   validity.Get("rangeOverflow").Bool(),
+		stepMismatch:    validity.Get("stepMismatch").Bool(),
+		badInput:        validity.Get("badInput").Bool(),
+	}
+	return state, obj.Get("validationMessage").String()
+}
+
+// formElement returns the html form element which the inputs in the form
+// belong to, or nil if there is none.
+func (form *Form) formElement() *js.Object {
+	for _, inputs := range form.Groups {
+		for _, el := range domElements(inputs) {
+			if formEl := el.Underlying().Get("form"); formEl != nil && formEl != js.Undefined {
+				return formEl
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// defaultMaxMemory is the maximum number of bytes of a multipart request body
+// which ParseRequest will store in memory. It matches the default used by the
+// net/http package.
+const defaultMaxMemory = 32 << 20
+
+// ParseValues creates and returns a Form object from the given values, e.g.
+// the parsed body of a form submission. It allows the same validations and
+// bindings to run on the server that run in the browser. Every value becomes a
+// separate Input, so a name with more than one value results in a group of
+// inputs (see Form.Groups). Because only the submitted values are known, each
+// Input has the type InputDefault and no attributes. Input.Bool and Input.Time
+// accept the values browsers submit for checkboxes and date and time inputs for
+// inputs with that type, so binding works the same way it does in the browser.
+func ParseValues(values url.Values) *Form {
+	// Sort the names so that the order of the inputs is deterministic.
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	form := NewForm()
+	for _, name := range names {
+		for _, value := range values[name] {
+			form.addInput(NewInput(valueElement{name: name, value: value}))
+		}
+	}
+	return form
+}
+
+// ParseRequest parses the form values of r (both from the url query and the
+// request body, including multipart bodies) and returns a Form object created
+// from them. See ParseValues. It returns an error if the request could not be
+// parsed.
+func ParseRequest(r *http.Request) (*Form, error) {
+	if err := r.ParseMultipartForm(defaultMaxMemory); err != nil && err != http.ErrNotMultipart {
+		return nil, err
+	}
+	return ParseValues(r.Form), nil
+}
+
+// valueElement is an Element which is backed by a single submitted value.
+type valueElement struct {
+	name  string
+	value string
+}
+
+// Name satisfies the Name method of Element.
+func (el valueElement) Name() string {
+	return el.name
+}
+
+// Type satisfies the Type method of Element. Since the original type is not
+// known, it always returns InputDefault.
+func (el valueElement) Type() InputType {
+	return InputDefault
+}
+
+// Value satisfies the Value method of Element.
+func (el valueElement) Value() string {
+	return el.value
+}
+
+// SelectedValues satisfies the SelectedValues method of Element.
+func (el valueElement) SelectedValues() []string {
+	return nil
+}
+
+// Checked satisfies the Checked method of Element. A submitted value always
+// counts as checked, since unchecked checkboxes and radio buttons are never
+// submitted.
+func (el valueElement) Checked() bool {
+	return true
+}
+
+// GetAttribute satisfies the GetAttribute method of Element. Submitted values
+// have no attributes, so it always returns an empty string.
+func (el valueElement) GetAttribute(name string) string {
+	return ""
+}
+
+// HasAttribute satisfies the HasAttribute method of Element. Submitted values
+// have no attributes, so it always returns false.
+func (el valueElement) HasAttribute(name string) bool {
+	return false
+}
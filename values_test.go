@@ -0,0 +1,118 @@
+// Copyright 2015 Alex Browne and Soroush Pour.
+// Allrights reserved. Use of this source code is
+// governed by the MIT license, which can be found
+// in the LICENSE file.
+
+package form
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseValues(t *testing.T) {
+	values := url.Values{
+		"name": {"Foo Bar"},
+		"age":  {"42"},
+		"ids":  {"1", "2", "3"},
+	}
+	form := ParseValues(values)
+	if got, _ := form.GetString("name"); got != "Foo Bar" {
+		t.Errorf("Expected name to be %q but got %q", "Foo Bar", got)
+	}
+	if got, _ := form.GetInt("age"); got != 42 {
+		t.Errorf("Expected age to be 42 but got %d", got)
+	}
+	if got, _ := form.GetInts("ids"); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("Expected ids to be [1 2 3] but got %v", got)
+	}
+	if got := form.Values(); !reflect.DeepEqual(got, values) {
+		t.Errorf("Expected form values to round trip.\nExpected: %v\nBut got:  %v", values, got)
+	}
+}
+
+func TestParseRequest(t *testing.T) {
+	// Test a url-encoded body combined with a query string.
+	req := httptest.NewRequest("POST", "/?page=2", strings.NewReader("name=foo&ids=1&ids=2"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	form, err := ParseRequest(req)
+	if err != nil {
+		t.Fatalf("Unexpected error from ParseRequest: %s", err)
+	}
+	expected := url.Values{
+		"page": {"2"},
+		"name": {"foo"},
+		"ids":  {"1", "2"},
+	}
+	if got := form.Values(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Incorrect form values.\nExpected: %v\nBut got:  %v", expected, got)
+	}
+
+	// Test a multipart body.
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	if err := writer.WriteField("name", "bar"); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	req = httptest.NewRequest("POST", "/", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	form, err = ParseRequest(req)
+	if err != nil {
+		t.Fatalf("Unexpected error from ParseRequest: %s", err)
+	}
+	if got, _ := form.GetString("name"); got != "bar" {
+		t.Errorf("Expected name to be %q but got %q", "bar", got)
+	}
+}
+
+func TestParseValuesBind(t *testing.T) {
+	values := url.Values{
+		"agree":   {"on"},
+		"born":    {"2020-01-02"},
+		"meeting": {"2020-01-02T15:04"},
+		"month":   {"2020-03"},
+		"week":    {"2020-W02"},
+		"alarm":   {"07:30"},
+		"created": {"2020-01-02T15:04:05Z"},
+	}
+	type Person struct {
+		Agree   bool
+		Born    time.Time
+		Meeting time.Time
+		Month   time.Time
+		Week    time.Time
+		Alarm   time.Time
+		Created time.Time
+	}
+	person := Person{}
+	if err := ParseValues(values).Bind(&person); err != nil {
+		t.Fatalf("Unexpected error from Bind: %s", err)
+	}
+	expected := Person{
+		Agree:   true,
+		Born:    time.Date(2020, time.January, 2, 0, 0, 0, 0, time.UTC),
+		Meeting: time.Date(2020, time.January, 2, 15, 4, 0, 0, time.UTC),
+		Month:   time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC),
+		Week:    time.Date(2020, time.January, 6, 0, 0, 0, 0, time.UTC),
+		Alarm:   time.Date(0, time.January, 1, 7, 30, 0, 0, time.UTC),
+		Created: time.Date(2020, time.January, 2, 15, 4, 5, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(person, expected) {
+		t.Errorf("Incorrect result from Bind.\nExpected: %+v\nBut got:  %+v", expected, person)
+	}
+
+	// Values which are not valid in any format should still fail.
+	invalid := url.Values{"agree": {"maybe"}, "born": {"yesterday"}}
+	if err := ParseValues(invalid).Bind(&Person{}); err == nil {
+		t.Error("Expected an error from Bind but got none")
+	}
+}